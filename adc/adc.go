@@ -13,87 +13,182 @@
 // @date 2022.04.08 re-enabled lastSoundPos; disabled threshold_low
 // @date 2022.04.09 changed threshold from 2.0V to 1.75V
 // @date 2022.04.18 removed import 'common'; const Tag* added locally
+// @date 2026.10.18 added Sampler and Indicator interfaces; capture loop moved to CaptureUint16;
+//                  machine dependent code moved to adc_rp2040.go, host samplers in adc_host.go
+// @date 2026.10.18 pre-trigger ring buffer; captures keep PreTriggerMs of sound before the threshold
+//                  sample, and the wait for threshold is paced at the sample period
+// @date 2026.10.18 CaptureVAD(); frame energy VAD, vad.go, replaces the adc_cap_threshold single sample
+//                  trigger and lastSoundPos trim
+// @date 2026.10.18 Interrupter; CaptureVAD gives up waiting for speech, e.g. for console input
+// @date 2026.10.18 Clocked and RateMeter; hardware clocked samplers at an exact rate, ClockDivider()
 
 package adc
 
-import (
-        "fmt"
-	"machine"
-	"time"
-)
-
-const adc_cap_threshold     = 35000 // 1.75V (/ (* 1.75 65536) 3.3) 34753
+// --obs-- const adc_cap_threshold     = 35000 // 1.75V (/ (* 1.75 65536) 3.3) 34753
 // --obs-- const adc_cap_threshold_low = 20000 // 1.0V (/ (* 1.0 65536) 3.3) 19859
 
+const PreTriggerMs = 20 // sound kept before speech starts, e.g. the 'f' onset of 'off'
+const Get_us       = 16 // machine.ADC.Get() time on pico; sample period is sleep_us + Get_us
+
 // general purpose tags; copied from 'common' and removed the localhost/common dependency
 const Tag_file     = "--file--"
 const Tag_eod      = "--eod--" // end of data
 const Tag_eot      = "--eot--" // end of transmission
 const Out_file     = "not-in-git.txt" // scratch file, e.g. created by dsp.Pull()
 
-// Cap2Uart captures 'buf_size' samples from adc with sample time of 'sleep_time' + Get() us
-func Cap2Uart(buf_size, sleep_time int) {
-	tag_file := Tag_file
-	tag_eod  := Tag_eod
-	tag_eot  := Tag_eot
-
-	buf := Cap2Uint16( buf_size, sleep_time )
-	
-	fmt.Printf("........%s--\n\r", tag_file)
-	for _,v := range buf {
-		fmt.Printf("%04x\n\r", v)
+// Sampler is the adc seen by the capture loop; implemented by the pico adc in
+// adc_rp2040.go and by replay/generator samplers in adc_host.go
+type Sampler interface {
+	Configure()             // initialize the adc; called once per capture
+	Get() uint16            // one 16 bit sample, 12 bit adc left justified
+	SetPeriod(sleep_us int) // sample clock; 'sleep_us' + Get() time per sample
+	Wait()                  // pace one sample period after Get()
+}
+
+// Indicator is a gpio signifier, e.g. machine.LED, high while capture is blocking
+type Indicator interface {
+	High()
+	Low()
+}
+
+// Exhauster is implemented by samplers with a finite supply of samples, e.g. file replay;
+// CaptureUint16 returns an empty buffer rather than block forever on an exhausted sampler
+type Exhauster interface {
+	Exhausted() bool
+}
+
+// Interrupter is implemented by samplers whose owner may need the cpu while blocking for
+// speech, e.g. for serial console input; CaptureVAD returns an empty buffer, Reason VADNone,
+// once Interrupted() while waiting. A capture in progress is not interrupted.
+type Interrupter interface {
+	Interrupted() bool
+}
+
+// ClockHz is the rp2040 adc clock, clk_adc; free running, the adc converts every 1 + DIV
+// clocks, DIV 16.8 fixed point, and every 96 clocks at most (500 kHz)
+const ClockHz = 48000000
+
+// Clocked is implemented by samplers clocked at a fixed rate by hardware, e.g. the rp2040
+// adc free running into its fifo, rather than paced by Wait() sleeping after Get(), whose
+// period is sleep_us + Get() time + loop jitter. Get() blocks for the next sample of the
+// clock and Wait() does nothing; SetPeriod(sleep_us) sets the clock to the nominal paced
+// rate, 1e6 / (sleep_us + Get_us), unless SetRate has set it.
+type Clocked interface {
+	SetRate(hz float64) (achieved float64) // set the sample clock; returns the rate its divider achieves
+	Overruns() int                          // samples lost since Configure(), e.g. to a full fifo
+}
+
+// RateMeter is implemented by samplers reporting the sample rate achieved, Hz; of the
+// clock divider for a Clocked sampler, measured over the last capture for a paced one
+type RateMeter interface {
+	Rate() float64
+}
+
+// ClockDivider returns the rp2040 adc DIV register value, 16.8 fixed point, nearest sample
+// rate 'hz', and the rate it achieves
+func ClockDivider(hz float64) (div uint32, achieved float64) {
+	period := int64(ClockHz*256/hz + 0.5) // clocks per sample, 1/256ths
+	if period < 96*256 {
+		period = 96*256
 	}
-	fmt.Printf("%s\n\r", tag_eod)
-	fmt.Printf("%s\n\r", tag_eot)
-} // end func Cap2Uart2(buf_size, sleep_time int) 
-
-// Cap2Uint16 captures, processes, and returns adc data; const local adc.go threshold values
-func Cap2Uint16(buf_size, sleep_us int) (buf []uint16){
-	threshold := adc_cap_threshold // const atop adc.go
-	// --obs-- threshold_low := adc_cap_threshold_low // const atop adc.go
-	machine.InitADC()
-	led := machine.LED
-	led.Configure(machine.PinConfig{Mode: machine.PinOutput})
-	sensor := machine.ADC{machine.ADC0}
-	sensor.Configure(machine.ADCConfig{})
+	if period > 0x10000*256 { // 16 bit INT
+		period = 0x10000*256
+	}
+	return uint32(period - 256), ClockHz*256/float64(period)
+} // end func ClockDivider
+
+// CaptureUint16 captures, processes, and returns up to 'buf_size' samples from 'sensor' with
+// sample time of 'sleep_us' + Get() us. 'led' is high while blocking for sound. The capture
+// starts PreTriggerMs before speech and ends with it, by DefaultVADParams.
+func CaptureUint16(sensor Sampler, led Indicator, buf_size, sleep_us int) (buf []uint16) {
+	return CaptureUint16Pre(sensor, led, buf_size, sleep_us, PreTriggerMs)
+} // end func CaptureUint16
+
+// PreTriggerSamples returns the count of samples in 'pre_ms' at a sample time of
+// 'sleep_us' + Get_us
+func PreTriggerSamples(pre_ms, sleep_us int) int {
+	return pre_ms * 1000 / (sleep_us + Get_us)
+}
+
+// CaptureUint16Pre is CaptureUint16 keeping 'pre_ms' of samples before speech starts
+func CaptureUint16Pre(sensor Sampler, led Indicator, buf_size, sleep_us, pre_ms int) (buf []uint16) {
+	buf, _ = CaptureVAD(sensor, led, buf_size, sleep_us, pre_ms, DefaultVADParams())
+	return buf
+} // end func CaptureUint16Pre
+
+// CaptureVAD captures one speech segment, detected by a VAD with params 'p', of up to
+// 'buf_size' samples. While blocking for speech, samples are paced at the sample period into
+// a circular buffer holding the pre-trigger and the VAD onset frames; once the VAD confirms
+// speech, the buffer from 'pre_ms' before its start begins 'buf', followed by the capture,
+// which ends when the VAD ends the segment or 'buf' is full. 'buf' is trimmed to the segment
+// end; 'seg' indexes 'buf'. An exhausted, or interrupted, sampler returns an empty buffer.
+func CaptureVAD(sensor Sampler, led Indicator, buf_size, sleep_us, pre_ms int, p VADParams) (buf []uint16, seg Segment) {
+	// --obs-- threshold := adc_cap_threshold; single sample trigger replaced by VAD
+	sensor.Configure()
+	sensor.SetPeriod(sleep_us)
+	exhauster, finite := sensor.(Exhauster)
+	interrupter, interruptible := sensor.(Interrupter)
 	// --obs-- assume caller handles ui: fmt.Printf("Tinygo/adc Cap2Uint16 --blocking--\n\r")
 	buf = make([]uint16, buf_size) // capture  buffer
-	val := sensor.Get() // uint16 disposable first adc read initializes val
-	led.High() // high when adc is blocking for threshold
-	for { // wait for adc to exceed threshold
-		val = sensor.Get() // uint16
-		// sound input threshold;
-		if val > uint16(threshold) {
-			break;
+	pre := PreTriggerSamples(pre_ms, sleep_us)
+	if pre > buf_size/2 { // leave at least half the buffer for the word
+		pre = buf_size/2
+	}
+	hist := pre + p.FrameLen*(p.MinSpeechFrames+1) // pre-trigger and onset frames
+	ring := make([]uint16, hist) // circular buffer; sample n at ring[n % hist]
+	vad := NewVAD(p)
+	sensor.Get() // uint16 disposable first adc read
+	led.High() // high when adc is blocking for speech
+	for !vad.Speaking() { // wait for speech
+		if (finite && exhauster.Exhausted()) || (interruptible && interrupter.Interrupted()) {
+			led.Low()
+			return buf[:0], Segment{Reason: VADNone, Clicks: vad.Clicks}
 		}
-		buf[0] = val // first sample excluded from range below
-	} // end wait for adc to exceed threshold
+		val := sensor.Get() // uint16
+		ring[vad.Len() % hist] = val
+		vad.Push(val)
+		sensor.Wait() // pace the ring at the sample period
+	} // end wait for speech
 	led.Low()
+	first := vad.Start - pre // stream index of buf[0]
+	if first < 0 {
+		first = 0
+	}
+	if first < vad.Len() - hist {
+		first = vad.Len() - hist
+	}
+	k := 0
+	for n:=first; n<vad.Len() && k<len(buf); n++ {
+		buf[k] = ring[n % hist]
+		k++
+	}
 	// --CAPTURE--
-	for i:=1; i<len(buf); i++ { // range buf adc get; already have buf[0]
+	for i:=k; i<len(buf); i++ { // range buf adc get; already have pre-trigger and onset samples
 		// 'Get()' takes ~16us on pico?; 70 us sleep -> 86 us/samp
 		// (+ 70 16) 86 (/ 1.0 86e-6) 11.6 Ksamp/sec
 		// (+ 300 16) 316 (/ 1.0 316e-6) 3.16 Ksamp/sec
+		if finite && exhauster.Exhausted() {
+			vad.Finish(VADExhausted)
+			break
+		}
 		buf[i] = sensor.Get() // uint16
-		time.Sleep(time.Microsecond * time.Duration(sleep_us))
+		if vad.Push(buf[i]) {
+			break
+		}
+		sensor.Wait()
 		// buf_size=2048, sleep_time=300 -> (* 316 2048 ) ~ 0.647168 second recording
 	} // end range buf
+	vad.Finish(VADFull) // no-op when the VAD ended the segment
 	// end --CAPTURE--
 	// fmt.Println("--debug-- buf[i]", buf[0:32], "\n\r")
 
-	lastSoundPos := len(buf)-1 // find end of sound over threshold, and prune
-	for i:=len(buf)-1; i>=0; i-- {
-		// --obs-- if buf[i] >= uint16(threshold) || buf[i] <= uint16(threshold_low) {
-		if buf[i] >= uint16(threshold) {
-			lastSoundPos = i
-			break
-		}
+	// --obs-- lastSoundPos trim; the VAD segment end, after hangover, trims quiet endings less
+	seg = Segment{Start: vad.Start - first, End: vad.End - first, Reason: vad.Reason, Clicks: vad.Clicks}
+	if seg.End > len(buf) {
+		seg.End = len(buf)
 	}
-
-	// lastSoundPos = len(buf)-1 // --dev-- 20220408 disables lastSoundPos
-
-	return buf[:lastSoundPos]
-} // end func Cap2Uint16
+	return buf[:seg.End], seg
+} // end func CaptureVAD
 
 // Notes:
 //
@@ -104,5 +199,3 @@ func Cap2Uint16(buf_size, sleep_us int) (buf []uint16){
 //		panic(e_th)
 //	}
 //
-
-
@@ -0,0 +1,153 @@
+// @file TinyGo/adc/adc_host.go
+// @date 2026.10.18
+// @info host (non pico) Samplers; replay captured samples from a file or slice, or
+//       generate them from a function, so the capture loop runs under 'go test'
+// @date 2026.10.18 added ClockedSampler; the host fake of a Clocked sampler
+
+//go:build !rp2040
+// +build !rp2040
+
+package adc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReplaySampler returns 'Samples' in order; Exhausted() once all have been read
+type ReplaySampler struct {
+	Samples  []uint16
+	Pos      int // index of next sample
+	Sleep_us int // last SetPeriod; replay does not sleep
+}
+
+// NewReplaySampler returns a sampler replaying 'samples'
+func NewReplaySampler(samples []uint16) *ReplaySampler {
+	return &ReplaySampler{Samples: samples}
+}
+
+// NewFileSampler returns a sampler replaying 'filename', one sample per line; 'base' 16 reads
+// the %04x Cap2Uart format, base 10 the captureDiags file00_xt.dat format
+func NewFileSampler(filename string, base int) (*ReplaySampler, error) {
+	samples, err := ReadSampleFile(filename, base)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplaySampler(samples), nil
+}
+
+func (s *ReplaySampler) Configure() {}
+
+// Get returns the next sample; 0 once exhausted
+func (s *ReplaySampler) Get() uint16 {
+	if s.Pos >= len(s.Samples) {
+		return 0
+	}
+	v := s.Samples[s.Pos]
+	s.Pos++
+	return v
+}
+
+func (s *ReplaySampler) SetPeriod(sleep_us int) { s.Sleep_us = sleep_us }
+func (s *ReplaySampler) Wait()                  {}
+
+// Exhausted is true once every sample has been returned by Get
+func (s *ReplaySampler) Exhausted() bool {
+	return s.Pos >= len(s.Samples)
+}
+
+// GenSampler returns Gen(n) for the n'th call to Get; never exhausted unless 'Len' > 0
+type GenSampler struct {
+	Gen      func(n int) uint16
+	Len      int // number of samples before Exhausted(); 0 is unlimited
+	N        int // count of samples returned
+	Sleep_us int
+}
+
+func (s *GenSampler) Configure() {}
+
+func (s *GenSampler) Get() uint16 {
+	v := s.Gen(s.N)
+	s.N++
+	return v
+}
+
+func (s *GenSampler) SetPeriod(sleep_us int) { s.Sleep_us = sleep_us }
+func (s *GenSampler) Wait()                  {}
+
+func (s *GenSampler) Exhausted() bool {
+	return s.Len > 0 && s.N >= s.Len
+}
+
+// ClockedSampler is the host fake of a Clocked sampler, e.g. the rp2040 FifoSampler; it
+// returns the samples of 'Source', recorded or resampled at the rate SetRate achieves, and
+// loses none
+type ClockedSampler struct {
+	Source Sampler
+	rate   float64
+	fixed  bool
+}
+
+// NewClockedSampler returns 'src' clocked at the ClockDivider rate nearest 'hz'
+func NewClockedSampler(src Sampler, hz float64) *ClockedSampler {
+	s := &ClockedSampler{Source: src}
+	s.SetRate(hz)
+	return s
+}
+
+func (s *ClockedSampler) Configure()  { s.Source.Configure() }
+func (s *ClockedSampler) Get() uint16 { return s.Source.Get() }
+func (s *ClockedSampler) Wait()       {}
+
+func (s *ClockedSampler) SetPeriod(sleep_us int) {
+	if !s.fixed {
+		_, s.rate = ClockDivider(1.0e6 / float64(sleep_us+Get_us))
+	}
+}
+
+func (s *ClockedSampler) SetRate(hz float64) float64 {
+	_, s.rate = ClockDivider(hz)
+	s.fixed = true
+	return s.rate
+}
+
+func (s *ClockedSampler) Rate() float64 { return s.rate }
+func (s *ClockedSampler) Overruns() int { return 0 }
+
+// Exhausted is the Source's; false for an unlimited Source
+func (s *ClockedSampler) Exhausted() bool {
+	e, ok := s.Source.(Exhauster)
+	return ok && e.Exhausted()
+}
+
+// NullIndicator discards High() and Low(); use where no led exists
+type NullIndicator struct{}
+
+func (NullIndicator) High() {}
+func (NullIndicator) Low()  {}
+
+// ReadSampleFile reads one uint16 sample per line of 'filename' in 'base' (16 or 10);
+// blank lines and '--' tag lines, e.g. Tag_file and Tag_eod, are skipped
+func ReadSampleFile(filename string, base int) (samples []uint16, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.Contains(line, "--") {
+			continue
+		}
+		v, err := strconv.ParseUint(line, base, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", filename, lineNum, err)
+		}
+		samples = append(samples, uint16(v))
+	}
+	return samples, scanner.Err()
+} // end func ReadSampleFile
@@ -0,0 +1,204 @@
+// @file TinyGo/adc/adc_rp2040.go
+// @date 2026.10.18
+// @info pico (rp2040) Sampler and Indicator; Cap2Uart and Cap2Uint16 moved here from adc.go
+// @date 2026.10.18 added Cap2Uint16Pre(); configurable pre-trigger ms
+// @date 2026.10.18 added Cap2VAD(); returns the VAD Segment of the capture
+// @date 2026.10.18 added SerialSampler, Cap2VADSerial(); serial input interrupts the wait for speech
+// @date 2026.10.18 added FifoSampler, SerialFifoSampler; the adc free running at the clock divider
+//                  rate into its fifo; PicoSampler measures its achieved rate
+
+// @build: tinygo flash -target=pico
+
+//go:build rp2040
+// +build rp2040
+
+package adc
+
+import (
+	"device/rp"
+	"fmt"
+	"machine"
+	"time"
+)
+
+// PicoSampler is the rp2040 adc on 'Pin', paced with time.Sleep
+type PicoSampler struct {
+	Pin      machine.Pin // e.g. machine.ADC0
+	sensor   machine.ADC
+	sleep_us int
+	n        int       // samples since Configure
+	t0, t1   time.Time // time of the first and last of them
+}
+
+// Configure initializes the rp2040 adc and 'Pin'
+func (s *PicoSampler) Configure() {
+	machine.InitADC()
+	s.sensor = machine.ADC{Pin: s.Pin}
+	s.sensor.Configure(machine.ADCConfig{})
+	s.n = 0
+}
+
+// Get returns one adc sample; takes ~16us on pico
+func (s *PicoSampler) Get() uint16 {
+	s.t1 = time.Now()
+	if s.n == 0 {
+		s.t0 = s.t1
+	}
+	s.n++
+	return s.sensor.Get()
+}
+
+// Rate returns the sample rate measured since Configure; the sleep, Get() and loop time
+func (s *PicoSampler) Rate() float64 {
+	if s.n < 2 || !s.t1.After(s.t0) {
+		return 0
+	}
+	return float64(s.n-1) / s.t1.Sub(s.t0).Seconds()
+}
+
+// SetPeriod sets the sleep between samples
+func (s *PicoSampler) SetPeriod(sleep_us int) {
+	s.sleep_us = sleep_us
+}
+
+// Wait sleeps 'sleep_us'
+func (s *PicoSampler) Wait() {
+	time.Sleep(time.Microsecond * time.Duration(s.sleep_us))
+}
+
+// SerialSampler is a PicoSampler interrupted by input on machine.Serial, e.g. console commands
+type SerialSampler struct {
+	PicoSampler
+}
+
+// Interrupted is true when machine.Serial has input buffered
+func (s *SerialSampler) Interrupted() bool {
+	return machine.Serial.Buffered() > 0
+}
+
+// FifoSampler is the rp2040 adc on 'Pin' free running into its 4 sample fifo, converting
+// every DIV clocks of ClockHz; an exact rate, without the jitter of sleep pacing. The fifo
+// holds ~1 ms at 4 kHz; Get() must be called at least that often.
+type FifoSampler struct {
+	Pin      machine.Pin // e.g. machine.ADC0
+	div      uint32      // DIV register, 16.8 fixed point
+	rate     float64     // achieved by div
+	fixed    bool        // SetRate was called; SetPeriod does not set the clock
+	overruns int
+}
+
+// SetRate sets the sample clock, from the next Configure(), to the DIV nearest 'hz'
+func (s *FifoSampler) SetRate(hz float64) (achieved float64) {
+	s.div, s.rate = ClockDivider(hz)
+	s.fixed = true
+	return s.rate
+}
+
+// Configure initializes the adc and 'Pin', empties the fifo, and starts the adc free running
+// at the SetRate clock; without SetRate, SetPeriod starts it
+func (s *FifoSampler) Configure() {
+	machine.InitADC() // resets the adc; stops a previous free run
+	machine.ADC{Pin: s.Pin}.Configure(machine.ADCConfig{})
+	rp.ADC.FCS.Set(rp.ADC_FCS_EN) // fifo on, 12 bit samples, no dma
+	for !rp.ADC.FCS.HasBits(rp.ADC_FCS_EMPTY) {
+		rp.ADC.FIFO.Get()
+	}
+	rp.ADC.FCS.SetBits(rp.ADC_FCS_OVER | rp.ADC_FCS_UNDER) // write 1 clears
+	s.overruns = 0
+	if s.fixed {
+		s.start()
+	}
+}
+
+// SetPeriod sets the clock to the nominal rate of sleep pacing, 1e6 / ('sleep_us' + Get_us),
+// and starts it, unless SetRate set the clock
+func (s *FifoSampler) SetPeriod(sleep_us int) {
+	if !s.fixed {
+		s.div, s.rate = ClockDivider(1.0e6 / float64(sleep_us+Get_us))
+		s.start()
+	}
+}
+
+func (s *FifoSampler) start() {
+	rp.ADC.DIV.Set(s.div)
+	rp.ADC.CS.ReplaceBits(uint32(s.Pin-machine.ADC0), 7, rp.ADC_CS_AINSEL_Pos)
+	rp.ADC.CS.SetBits(rp.ADC_CS_START_MANY)
+}
+
+// Get blocks for the next fifo sample; 12 bits left justified, as machine.ADC.Get()
+func (s *FifoSampler) Get() uint16 {
+	for rp.ADC.FCS.HasBits(rp.ADC_FCS_EMPTY) {
+	}
+	if rp.ADC.FCS.HasBits(rp.ADC_FCS_OVER) { // the fifo was full; samples were lost
+		s.overruns++
+		rp.ADC.FCS.SetBits(rp.ADC_FCS_OVER)
+	}
+	return uint16(rp.ADC.FIFO.Get()&0xFFF) << 4
+}
+
+// Wait does nothing; Get waits for the clock
+func (s *FifoSampler) Wait() {}
+
+// Rate returns the sample rate of the clock divider
+func (s *FifoSampler) Rate() float64 { return s.rate }
+
+// Overruns returns the count of fifo overflows since Configure; each lost samples
+func (s *FifoSampler) Overruns() int { return s.overruns }
+
+// Stop stops the adc free running, e.g. before sleeping between captures
+func (s *FifoSampler) Stop() {
+	rp.ADC.CS.ClearBits(rp.ADC_CS_START_MANY)
+}
+
+// SerialFifoSampler is a FifoSampler interrupted by input on machine.Serial
+type SerialFifoSampler struct {
+	FifoSampler
+}
+
+// Interrupted is true when machine.Serial has input buffered
+func (s *SerialFifoSampler) Interrupted() bool {
+	return machine.Serial.Buffered() > 0
+}
+
+// Cap2Uart captures 'buf_size' samples from adc with sample time of 'sleep_time' + Get() us
+func Cap2Uart(buf_size, sleep_time int) {
+	tag_file := Tag_file
+	tag_eod  := Tag_eod
+	tag_eot  := Tag_eot
+
+	buf := Cap2Uint16( buf_size, sleep_time )
+	
+	fmt.Printf("........%s--\n\r", tag_file)
+	for _,v := range buf {
+		fmt.Printf("%04x\n\r", v)
+	}
+	fmt.Printf("%s\n\r", tag_eod)
+	fmt.Printf("%s\n\r", tag_eot)
+} // end func Cap2Uart2(buf_size, sleep_time int) 
+
+// Cap2Uint16 captures, processes, and returns adc data from machine.ADC0; machine.LED
+// is high while blocking for sound
+func Cap2Uint16(buf_size, sleep_us int) (buf []uint16){
+	return Cap2Uint16Pre(buf_size, sleep_us, PreTriggerMs)
+} // end func Cap2Uint16
+
+// Cap2Uint16Pre is Cap2Uint16 keeping 'pre_ms' of samples before speech starts
+func Cap2Uint16Pre(buf_size, sleep_us, pre_ms int) (buf []uint16){
+	buf, _ = Cap2VAD(buf_size, sleep_us, pre_ms, DefaultVADParams())
+	return buf
+} // end func Cap2Uint16Pre
+
+// Cap2VAD is CaptureVAD on machine.ADC0; machine.LED is high while blocking for speech
+func Cap2VAD(buf_size, sleep_us, pre_ms int, p VADParams) (buf []uint16, seg Segment){
+	led := machine.LED
+	led.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	return CaptureVAD(&PicoSampler{Pin: machine.ADC0}, led, buf_size, sleep_us, pre_ms, p)
+} // end func Cap2VAD
+
+// Cap2VADSerial is Cap2VAD returning an empty buffer, Reason VADNone, when machine.Serial
+// input arrives while blocking for speech
+func Cap2VADSerial(buf_size, sleep_us, pre_ms int, p VADParams) (buf []uint16, seg Segment){
+	led := machine.LED
+	led.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	return CaptureVAD(&SerialSampler{PicoSampler{Pin: machine.ADC0}}, led, buf_size, sleep_us, pre_ms, p)
+} // end func Cap2VADSerial
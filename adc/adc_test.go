@@ -0,0 +1,175 @@
+// @file TinyGo/adc/adc_test.go
+// @date 2026.10.18
+// @info CaptureUint16 VAD capture and pre-trigger on generated and replayed samples;
+//       ReadSampleFile formats; ClockDivider limits
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package adc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	silent = 0x8000 // DefaultVADParams DC
+	sound  = 0x4000 // +- DC; frame energy far above DefaultVADParams StartEnergy
+)
+
+// burst returns 'n' samples, silent except for sound at [from, to), alternating DC +- sound
+func burst(n, from, to int) []uint16 {
+	s := make([]uint16, n)
+	for i := range s {
+		s[i] = silent + uint16(i%16) // index in the low bits, below the VAD, to tell samples apart
+		if i >= from && i < to {
+			if i%2 == 0 {
+				s[i] += sound
+			} else {
+				s[i] -= sound
+			}
+		}
+	}
+	return s
+}
+
+func TestCaptureUint16(t *testing.T) {
+	// 100 us samples, DefaultVADParams 32 sample frames; PreTriggerMs is 200 samples. The
+	// stream starts with the disposable first Get, so VAD frames start at stream[1].
+	const sleepUs = 84
+	tests := []struct {
+		name     string
+		from, to int // sound in the sampler stream
+		bufSize  int
+		preMs    int
+		first    int // buf is stream[first:last]
+		last     int
+	}{
+		{"word", 321, 641, 1024, PreTriggerMs, 121, 641},
+		{"pre_ms 1", 321, 641, 1024, 1, 311, 641},
+		{"pre_ms 0", 321, 641, 1024, 0, 321, 641},
+		{"buffer full", 321, 3000, 512, PreTriggerMs, 121, 633},
+		{"pre-trigger capped at half the buffer", 321, 641, 256, PreTriggerMs, 193, 449},
+		{"sound at the first sample", 1, 321, 1024, PreTriggerMs, 1, 321},
+		{"sound ends within a frame", 321, 630, 1024, PreTriggerMs, 121, 641},
+	}
+	for _, tt := range tests {
+		stream := burst(4000, tt.from, tt.to)
+		s := &GenSampler{Gen: func(n int) uint16 { return stream[n] }}
+		buf := CaptureUint16Pre(s, NullIndicator{}, tt.bufSize, sleepUs, tt.preMs)
+		if want := stream[tt.first:tt.last]; !reflect.DeepEqual(buf, want) {
+			t.Errorf("%s: buf of %d samples is not stream[%d:%d]", tt.name, len(buf), tt.first, tt.last)
+		}
+		if s.Sleep_us != sleepUs {
+			t.Errorf("%s: SetPeriod %d, want %d", tt.name, s.Sleep_us, sleepUs)
+		}
+	}
+	stream := burst(4000, 321, 641)
+	s := &GenSampler{Gen: func(n int) uint16 { return stream[n] }}
+	if buf := CaptureUint16(s, NullIndicator{}, 1024, sleepUs); !reflect.DeepEqual(buf, stream[121:641]) {
+		t.Errorf("CaptureUint16 is not CaptureUint16Pre with PreTriggerMs: %d samples", len(buf))
+	}
+}
+
+func TestCaptureUint16Exhausted(t *testing.T) {
+	s := NewReplaySampler(burst(100, 200, 300)) // never reaches threshold
+	if buf := CaptureUint16(s, NullIndicator{}, 64, 84); len(buf) != 0 {
+		t.Errorf("silent replay: %d samples, want none", len(buf))
+	}
+	g := &GenSampler{Gen: func(n int) uint16 { return silent }, Len: 50}
+	if buf := CaptureUint16(g, NullIndicator{}, 64, 84); len(buf) != 0 {
+		t.Errorf("silent generator: %d samples, want none", len(buf))
+	}
+	if g.N != 50 {
+		t.Errorf("generator read %d samples, want its Len 50", g.N)
+	}
+}
+
+func TestFileSampler(t *testing.T) {
+	stream := burst(1000, 321, 641)
+	var hex, dec strings.Builder
+	fmt.Fprintf(&hex, "%s\n", Tag_file)
+	for _, v := range stream {
+		fmt.Fprintf(&hex, "%04x\n", v)
+		fmt.Fprintf(&dec, "%d\n\n", v)
+	}
+	fmt.Fprintf(&hex, "%s\n", Tag_eod)
+	dir := t.TempDir()
+	for _, f := range []struct {
+		name, text string
+		base       int
+	}{{"cap.txt", hex.String(), 16}, {"file00_xt.dat", dec.String(), 10}} {
+		name := filepath.Join(dir, f.name)
+		if err := os.WriteFile(name, []byte(f.text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := NewFileSampler(name, f.base)
+		if err != nil {
+			t.Fatalf("%s: %v", f.name, err)
+		}
+		if !reflect.DeepEqual(s.Samples, stream) {
+			t.Fatalf("%s: samples differ from those written", f.name)
+		}
+		buf := CaptureUint16(s, NullIndicator{}, 1024, 84)
+		if want := stream[121:641]; !reflect.DeepEqual(buf, want) {
+			t.Errorf("%s: buf of %d samples is not stream[121:641]", f.name, len(buf))
+		}
+	}
+}
+
+func TestReadSampleFileError(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "bad.txt")
+	if err := os.WriteFile(name, []byte("8000\n\n8001\nzz\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadSampleFile(name, 16); err == nil || !strings.Contains(err.Error(), ":4:") {
+		t.Errorf("ReadSampleFile error %v, want one at line 4", err)
+	}
+	if _, err := ReadSampleFile(filepath.Join(t.TempDir(), "none.txt"), 16); err == nil {
+		t.Error("ReadSampleFile of a missing file: no error")
+	}
+}
+
+func TestClockDivider(t *testing.T) {
+	tests := []struct {
+		name     string
+		hz       float64
+		div      uint32
+		achieved float64
+	}{
+		{"pico paced rate", 1e6 / 266, 12767 << 8, 1e6 / 266},
+		{"fraction", 7000, 1755429 - 256, ClockHz * 256 / 1755429.0},
+		{"96 clocks", 500000, 95 << 8, 500000},
+		{"96.5 clocks", ClockHz / 96.5, 95<<8 + 128, ClockHz / 96.5},
+		{"above 96 clock min", 600000, 95 << 8, 500000},
+		{"INT 0xFFFE", ClockHz / 65535.0, 0xFFFE << 8, ClockHz / 65535.0},
+		{"INT 0xFFFF", ClockHz / 65536.0, 0xFFFF << 8, ClockHz / 65536.0},
+		{"below INT clamp", 100, 0xFFFF << 8, ClockHz / 65536.0},
+	}
+	for _, tt := range tests {
+		div, achieved := ClockDivider(tt.hz)
+		if div != tt.div || achieved != tt.achieved {
+			t.Errorf("%s: ClockDivider(%v) = %#x, %v; want %#x, %v", tt.name, tt.hz, div, achieved, tt.div, tt.achieved)
+		}
+	}
+
+	s := NewClockedSampler(&GenSampler{Gen: func(n int) uint16 { return silent }, Len: 1}, 600000)
+	if s.Rate() != 500000 {
+		t.Errorf("ClockedSampler rate %v, want the 500000 clamp", s.Rate())
+	}
+	s.SetPeriod(250) // SetRate fixed the rate
+	if s.Rate() != 500000 {
+		t.Errorf("ClockedSampler rate after SetPeriod %v, want 500000", s.Rate())
+	}
+	if s.Get(); !s.Exhausted() {
+		t.Error("ClockedSampler of an exhausted Source not Exhausted")
+	}
+}
@@ -0,0 +1,118 @@
+// @file TinyGo/adc/noise.go
+// @date 2026.10.18
+// @info ambient noise floor estimate, tracked between utterances, and the VAD and noise
+//       rejection thresholds derived from it; replaces thresholds tuned to one room
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: tinygo flash -target=pico, or go build as a dependency of host tools
+
+package adc
+
+// NoiseParams tune NoiseFloor. Energies are VAD frame energies, the mean square in 12 bit
+// adc counts; ratios are of the floor energy, e.g. 16 is 12 dB above the floor.
+type NoiseParams struct {
+	Init       int // floor energy before the first frame, e.g. 100 is 10 counts rms
+	RiseShift  int // floor rises by 1/2^RiseShift of a louder frame's excess; slow, so speech onsets do not lift it
+	FullShift  int // RiseShift toward the quietest frame of a capture ending VADFull; learns noise too loud for the floor
+	FallShift  int // floor falls by 1/2^FallShift; fast, to recover after a noise burst
+	StartRatio int // VAD StartEnergy is StartRatio x floor
+	StopRatio  int // VAD StopEnergy is StopRatio x floor
+	MinStart   int // StartEnergy lower bound, above adc noise in a quiet room
+	MinStop    int // StopEnergy lower bound
+	PeakRatio  int // noise rejection: a capture peak below PeakRatio x floor rms above DC is noise
+	MinPeak    int // PeakThreshold lower bound above DC, 16 bit
+}
+
+// DefaultNoiseParams returns --prod-- noise floor params; at the VAD 8.5 ms frames the floor
+// rises with a ~270 ms time constant and falls within ~35 ms; captures of constant noise,
+// which fill the buffer, halve the distance to it
+func DefaultNoiseParams() NoiseParams {
+	return NoiseParams{Init: 100, RiseShift: 5, FullShift: 1, FallShift: 2, StartRatio: 16, StopRatio: 4,
+		MinStart: 1600, MinStop: 300, PeakRatio: 16, MinPeak: 0x0800}
+}
+
+// NoiseFloor tracks the ambient noise energy of frames outside speech; set VADParams.Noise
+// to adapt a VAD's thresholds to it. Keep one NoiseFloor across captures.
+type NoiseFloor struct {
+	P      NoiseParams
+	Energy int // current floor estimate, VAD frame energy
+	Frames int // frames the estimate is based on
+}
+
+// NewNoiseFloor returns a NoiseFloor at p.Init
+func NewNoiseFloor(p NoiseParams) *NoiseFloor {
+	return &NoiseFloor{P: p, Energy: p.Init}
+}
+
+// Update adds the energy 'e' of a frame outside speech to the estimate
+func (n *NoiseFloor) Update(e int) {
+	n.update(e, n.P.RiseShift)
+}
+
+// UpdateFull adds 'e', the quietest frame energy of a capture ending VADFull, to the
+// estimate; a capture full of sound without a pause is more likely noise than a word, and
+// noise too loud for the floor would otherwise hold the VAD in speech and never be learnt
+func (n *NoiseFloor) UpdateFull(e int) {
+	n.update(e, n.P.FullShift)
+}
+
+func (n *NoiseFloor) update(e, riseShift int) {
+	if e > n.Energy {
+		n.Energy += (e - n.Energy) >> riseShift
+	} else {
+		n.Energy -= (n.Energy - e) >> n.P.FallShift
+	}
+	if n.Energy < 1 {
+		n.Energy = 1
+	}
+	n.Frames++
+}
+
+// Thresholds returns the VAD start and stop energies relative to the floor
+func (n *NoiseFloor) Thresholds() (start, stop int) {
+	start = n.Energy * n.P.StartRatio
+	if start < n.P.MinStart {
+		start = n.P.MinStart
+	}
+	stop = n.Energy * n.P.StopRatio
+	if stop < n.P.MinStop {
+		stop = n.P.MinStop
+	}
+	return start, stop
+}
+
+// Rms returns the floor as rms 12 bit adc counts
+func (n *NoiseFloor) Rms() int {
+	return isqrt(n.Energy)
+}
+
+// PeakThreshold returns the 16 bit sample a capture must exceed not to be noise, for
+// dsp.NormalizeU16_ac_threshold; replaces its fixed 0xBFFF. 'dc' is the silent sample, e.g.
+// VADParams.DC; capped below the 0xFFF0 clipping check.
+func (n *NoiseFloor) PeakThreshold(dc uint16) uint16 {
+	peak := (n.Rms() * n.P.PeakRatio) << 4
+	if peak < n.P.MinPeak {
+		peak = n.P.MinPeak
+	}
+	if t := int(dc) + peak; t < 0xFFF0 {
+		return uint16(t)
+	}
+	return 0xFFEF
+}
+
+// isqrt returns the integer square root of 'v' >= 0; Newton's method, no float on the pico
+func isqrt(v int) int {
+	if v < 2 {
+		return v
+	}
+	x := v
+	y := (x + 1) / 2
+	for y < x {
+		x = y
+		y = (x + v/x) / 2
+	}
+	return x
+}
@@ -0,0 +1,143 @@
+// @file TinyGo/adc/noise_test.go
+// @date 2026.10.18
+// @info NoiseFloor tracking, loud frame rejection, thresholds and isqrt
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package adc
+
+import "testing"
+
+func TestNoiseFloorConverges(t *testing.T) {
+	p := DefaultNoiseParams()
+	tests := []struct {
+		name   string
+		e      int
+		frames int
+		shift  int // the floor stops within 2^shift of 'e', where the shifted step is 0
+	}{
+		{"rise", 900, 300, p.RiseShift},
+		{"fall", 20, 40, p.FallShift},
+		{"steady", p.Init, 10, 0},
+	}
+	for _, tt := range tests {
+		n := NewNoiseFloor(p)
+		last := n.Energy
+		for i := 0; i < tt.frames; i++ {
+			n.Update(tt.e)
+			if (tt.e > p.Init && n.Energy < last) || (tt.e < p.Init && n.Energy > last) {
+				t.Fatalf("%s: frame %d moved away from %d, %d to %d", tt.name, i, tt.e, last, n.Energy)
+			}
+			last = n.Energy
+		}
+		d := tt.e - n.Energy
+		if d < 0 {
+			d = -d
+		}
+		if d >= 1<<tt.shift {
+			t.Errorf("%s: floor %d after %d frames of %d, want within %d", tt.name, n.Energy, tt.frames, tt.e, 1<<tt.shift)
+		}
+		if n.Frames != tt.frames {
+			t.Errorf("%s: Frames %d, want %d", tt.name, n.Frames, tt.frames)
+		}
+	}
+}
+
+func TestNoiseFloorRates(t *testing.T) {
+	p := DefaultNoiseParams()
+	n := NewNoiseFloor(p) // 100
+	n.Update(100 + 320)   // rises by 1/32 of the excess
+	if n.Energy != 110 {
+		t.Errorf("rise: %d, want 110", n.Energy)
+	}
+	n.UpdateFull(110 + 200) // a full capture's quietest frame rises by 1/2
+	if n.Energy != 210 {
+		t.Errorf("full rise: %d, want 210", n.Energy)
+	}
+	n.Update(10) // falls by 1/4
+	if n.Energy != 160 {
+		t.Errorf("fall: %d, want 160", n.Energy)
+	}
+	n.P.FallShift = 0 // falls all the way, but never below 1
+	n.Update(0)
+	if n.Energy != 1 {
+		t.Errorf("floor %d after silence, want 1", n.Energy)
+	}
+}
+
+func TestNoiseFloorRejectsSpeech(t *testing.T) {
+	nf := NewNoiseFloor(DefaultNoiseParams())
+	p := testVAD
+	p.Noise = nf
+	start, _ := nf.Thresholds()
+	// quiet frames update the floor; speech frames, at or above StartEnergy, do not
+	v := NewVAD(p)
+	for _, s := range frames(mid, mid, mid) { // energy 100, the Init floor
+		v.Push(s)
+	}
+	if nf.Frames != 3 {
+		t.Fatalf("%d floor updates from 3 quiet frames, want 3", nf.Frames)
+	}
+	loudFrames := frames(0)
+	level := 1
+	for level*level < start { // rms at StartEnergy
+		level++
+	}
+	for i := range loudFrames {
+		d := level << 4
+		if i%2 == 1 {
+			d = -d
+		}
+		loudFrames[i] = uint16(int(p.DC) + d)
+	}
+	for k := 0; k < 4; k++ {
+		for _, s := range loudFrames {
+			v.Push(s)
+		}
+	}
+	if nf.Frames != 3 || nf.Energy != 100 {
+		t.Errorf("floor %d after %d updates, want 100 after 3; speech frames updated it", nf.Energy, nf.Frames)
+	}
+}
+
+func TestNoiseFloorThresholds(t *testing.T) {
+	p := DefaultNoiseParams()
+	n := NewNoiseFloor(p)
+	tests := []struct {
+		energy      int
+		start, stop int
+		peak        uint16 // PeakThreshold(0x8000)
+	}{
+		{1, p.MinStart, p.MinStop, 0x8000 + uint16(p.MinPeak)}, // lower bounds in a quiet room
+		{100, 1600, 400, 0x8000 + 0x0A00},                      // rms 10
+		{10000, 160000, 40000, 0x8000 + 0x6400},                // rms 100
+		{1 << 20, 16 << 20, 4 << 20, 0xFFEF},                   // capped below the clipping check
+	}
+	for _, tt := range tests {
+		n.Energy = tt.energy
+		start, stop := n.Thresholds()
+		if start != tt.start || stop != tt.stop {
+			t.Errorf("energy %d: Thresholds %d, %d, want %d, %d", tt.energy, start, stop, tt.start, tt.stop)
+		}
+		if got := n.PeakThreshold(0x8000); got != tt.peak {
+			t.Errorf("energy %d: PeakThreshold %#x, want %#x", tt.energy, got, tt.peak)
+		}
+	}
+}
+
+func TestIsqrt(t *testing.T) {
+	tests := []struct{ v, want int }{
+		{0, 0}, {1, 1}, {2, 1}, {3, 1}, {4, 2}, {8, 2}, {9, 3}, {15, 3}, {16, 4}, {17, 4},
+		{99, 9}, {100, 10}, {1 << 30, 1 << 15}, {65535 * 65535, 65535}, {65536*65536 - 1, 65535},
+		{1<<32 - 1, 65535}, // max uint32
+	}
+	for _, tt := range tests {
+		if got := isqrt(tt.v); got != tt.want {
+			t.Errorf("isqrt(%d) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// @file TinyGo/adc/vad.go
+// @date 2026.10.18
+// @info frame energy voice activity detector; starts and ends captures on the energy of
+//       FrameLen sample frames, with hysteresis, hangover and a minimum speech length,
+//       rather than on single samples over adc_cap_threshold
+// @date 2026.10.18 VADParams.Noise; start and stop energies relative to a NoiseFloor updated between utterances
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: tinygo flash -target=pico, or go build as a dependency of host tools
+
+package adc
+
+// VADReason is why a VAD segment ended
+type VADReason int
+
+const (
+	VADNone      VADReason = iota // no speech yet, or speech in progress
+	VADEnd                        // energy below StopEnergy for HangoverFrames
+	VADFull                       // capture buffer full during speech
+	VADExhausted                  // sampler, or stream, ended during speech
+)
+
+func (r VADReason) String() string {
+	switch r {
+	case VADNone:
+		return "none"
+	case VADEnd:
+		return "end"
+	case VADFull:
+		return "full"
+	case VADExhausted:
+		return "exhausted"
+	}
+	return "unknown"
+}
+
+// VADParams tune the VAD. Frame energy is the mean square deviation of a frame's samples
+// from DC, in 12 bit adc counts; e.g. energy 4900 is 70 counts rms, 56 mV.
+type VADParams struct {
+	FrameLen        int         // samples per energy frame
+	DC              uint16      // adc sample at silence; the mic amplifier bias
+	StartEnergy     int         // frame energy at or above starts speech
+	StopEnergy      int         // frame energy below is quiet; speech ends after HangoverFrames quiet frames
+	HangoverFrames  int         // quiet frames kept inside speech, e.g. stop consonants and quiet endings
+	MinSpeechFrames int         // frames at or above StopEnergy before a start is speech; shorter is a click
+	Noise           *NoiseFloor // optional; Noise.Thresholds() replace StartEnergy and StopEnergy
+}
+
+// DefaultVADParams returns --prod-- VAD params for the 266 us pico sample time: 8.5 ms
+// frames, 100 ms hangover, 25 ms minimum speech
+func DefaultVADParams() VADParams {
+	return VADParams{FrameLen: 32, DC: 0x8000, StartEnergy: 4900, StopEnergy: 900,
+		HangoverFrames: 12, MinSpeechFrames: 3}
+}
+
+// VAD states
+const (
+	vadIdle     = iota // waiting for StartEnergy
+	vadOnset           // above StartEnergy, fewer than MinSpeechFrames
+	vadSpeech          // speech confirmed
+	vadHangover        // speech, counting quiet frames
+	vadDone            // segment ended; Push ignores samples
+)
+
+// VAD is a streaming voice activity detector; Push samples in order. Start and End are
+// sample indices into the pushed stream, End exclusive; Start is valid once Speaking(),
+// End once Push returns true or after Finish.
+type VAD struct {
+	P      VADParams
+	Start  int       // first sample of the first speech frame
+	End    int       // one past the last sample of the last speech frame
+	Reason VADReason // why the segment ended; VADNone while in progress
+	Clicks int       // starts discarded as shorter than MinSpeechFrames
+	Energy int       // energy of the last complete frame
+
+	n           int // samples pushed
+	acc         int // frame energy accumulator
+	accN        int // samples in acc
+	state       int
+	onsetFrames int
+	quietFrames int
+	minEnergy   int // quietest frame since the start
+}
+
+// NewVAD returns a VAD with params 'p'
+func NewVAD(p VADParams) *VAD {
+	return &VAD{P: p}
+}
+
+// Reset clears the VAD for a new segment; Clicks are kept
+func (v *VAD) Reset() {
+	*v = VAD{P: v.P, Clicks: v.Clicks}
+}
+
+// Speaking is true once speech has started; Start is valid
+func (v *VAD) Speaking() bool {
+	return v.state == vadSpeech || v.state == vadHangover || (v.state == vadDone && v.Reason != VADNone)
+}
+
+// Thresholds returns the start and stop energies in use; relative to P.Noise when set
+func (v *VAD) Thresholds() (start, stop int) {
+	if v.P.Noise != nil {
+		return v.P.Noise.Thresholds()
+	}
+	return v.P.StartEnergy, v.P.StopEnergy
+}
+
+// Len returns the count of samples pushed
+func (v *VAD) Len() int { return v.n }
+
+// Push adds sample 's' to the stream; returns true once the speech segment has ended
+func (v *VAD) Push(s uint16) (done bool) {
+	if v.state == vadDone {
+		return true
+	}
+	d := int(s>>4) - int(v.P.DC>>4) // 12 bit counts; d*d*FrameLen fits a 32 bit int for FrameLen < 512
+	v.acc += d * d
+	v.accN++
+	v.n++
+	if v.accN < v.P.FrameLen {
+		return false
+	}
+	v.Energy = v.acc / v.accN
+	v.acc, v.accN = 0, 0
+	return v.frame(v.Energy)
+}
+
+// frame advances the state machine on the energy 'e' of the frame ending at sample v.n
+func (v *VAD) frame(e int) (done bool) {
+	frameStart := v.n - v.P.FrameLen
+	startEnergy, stopEnergy := v.Thresholds()
+	loud := e >= stopEnergy // inside speech, hysteresis keeps frames down to StopEnergy
+	if v.state != vadIdle && e < v.minEnergy {
+		v.minEnergy = e
+	}
+	switch v.state {
+	case vadIdle:
+		if e < startEnergy && v.P.Noise != nil {
+			v.P.Noise.Update(e)
+		}
+		if e >= startEnergy {
+			v.minEnergy = e
+			v.Start = frameStart
+			v.End = v.n
+			v.onsetFrames = 1
+			v.state = vadOnset
+		}
+	case vadOnset:
+		if !loud {
+			v.Clicks++
+			v.state = vadIdle
+			break
+		}
+		v.End = v.n
+		v.onsetFrames++
+	case vadSpeech, vadHangover:
+		if loud {
+			v.End = v.n
+			v.quietFrames = 0
+			v.state = vadSpeech
+			break
+		}
+		v.quietFrames++
+		v.state = vadHangover
+	}
+	if v.state == vadOnset && v.onsetFrames >= v.P.MinSpeechFrames {
+		v.state = vadSpeech
+	}
+	if v.state == vadHangover && v.quietFrames >= v.P.HangoverFrames {
+		v.Finish(VADEnd)
+		return true
+	}
+	return false
+} // end func frame
+
+// Finish ends the segment for 'reason', e.g. VADFull or VADExhausted; End is the end of
+// the last speech frame, or of the samples pushed during a partial frame of speech
+func (v *VAD) Finish(reason VADReason) {
+	if v.state == vadDone {
+		return
+	}
+	if v.state == vadSpeech && v.accN > 0 {
+		v.End = v.n
+	}
+	if !v.Speaking() {
+		reason = VADNone
+	}
+	if reason == VADFull && v.P.Noise != nil {
+		v.P.Noise.UpdateFull(v.minEnergy)
+	}
+	v.Reason = reason
+	v.state = vadDone
+}
+
+// Segment is a VAD speech segment of a capture buffer
+type Segment struct {
+	Start  int // first speech sample; samples before are the pre-trigger
+	End    int // one past the last speech sample; the capture is trimmed to End
+	Reason VADReason
+	Clicks int // clicks ignored while waiting for speech
+}
+
+// DetectSpeech runs a VAD with params 'p' over 'samples' and returns the first speech
+// segment; Reason VADNone when there is none
+func DetectSpeech(samples []uint16, p VADParams) Segment {
+	v := NewVAD(p)
+	for _, s := range samples {
+		if v.Push(s) {
+			break
+		}
+	}
+	v.Finish(VADExhausted)
+	if v.Reason == VADNone {
+		return Segment{Reason: VADNone, Clicks: v.Clicks}
+	}
+	return Segment{Start: v.Start, End: v.End, Reason: v.Reason, Clicks: v.Clicks}
+}
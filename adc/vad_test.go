@@ -0,0 +1,147 @@
+// @file TinyGo/adc/vad_test.go
+// @date 2026.10.18
+// @info VAD trigger, hysteresis and hangover, and CaptureVAD's pre-trigger ring, on host
+//       samplers
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package adc
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testVAD is small enough to count frames by hand: 4 sample frames, start at 20 counts rms,
+// stop below 5, 2 hangover frames, 2 frames to confirm speech
+var testVAD = VADParams{FrameLen: 4, DC: 0x8000, StartEnergy: 400, StopEnergy: 25,
+	HangoverFrames: 2, MinSpeechFrames: 2}
+
+const (
+	quiet = 0  // energy 0
+	mid   = 10 // energy 100; between StopEnergy and StartEnergy
+	loud  = 30 // energy 900
+)
+
+// frames returns one testVAD frame per level, samples alternating DC +- level counts
+func frames(levels ...int) []uint16 {
+	var s []uint16
+	for _, l := range levels {
+		for i := 0; i < testVAD.FrameLen; i++ {
+			d := l
+			if i%2 == 1 {
+				d = -l
+			}
+			s = append(s, uint16(int(testVAD.DC)+d<<4))
+		}
+	}
+	return s
+}
+
+func TestDetectSpeech(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels []int
+		want   Segment
+	}{
+		{"silence", []int{quiet, quiet, quiet}, Segment{Reason: VADNone}},
+		{"below start", []int{mid, mid, mid, quiet}, Segment{Reason: VADNone}},
+		{"click", []int{quiet, loud, quiet, quiet, quiet}, Segment{Reason: VADNone, Clicks: 1}},
+		{"word", []int{quiet, quiet, loud, loud, loud, quiet, quiet, quiet},
+			Segment{Start: 8, End: 20, Reason: VADEnd}},
+		{"hangover bridges a gap", []int{loud, loud, loud, quiet, loud, loud, quiet, quiet},
+			Segment{Start: 0, End: 24, Reason: VADEnd}},
+		{"hysteresis keeps mid frames", []int{loud, loud, mid, mid, quiet, quiet},
+			Segment{Start: 0, End: 16, Reason: VADEnd}},
+		{"click then word", []int{loud, quiet, loud, loud, quiet, quiet},
+			Segment{Start: 8, End: 16, Reason: VADEnd, Clicks: 1}},
+		{"exhausted in speech", []int{quiet, loud, loud, loud}, Segment{Start: 4, End: 16, Reason: VADExhausted}},
+	}
+	for _, tt := range tests {
+		if got := DetectSpeech(frames(tt.levels...), testVAD); got != tt.want {
+			t.Errorf("%s: DetectSpeech = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVADNoiseThresholds(t *testing.T) {
+	p := testVAD
+	p.StartEnergy, p.StopEnergy = 1<<30, 1<<30 // ignored with a NoiseFloor
+	nf := NewNoiseFloor(DefaultNoiseParams())
+	p.Noise = nf
+	v := NewVAD(p)
+	start, stop := v.Thresholds()
+	if wantStart, wantStop := nf.Thresholds(); start != wantStart || stop != wantStop {
+		t.Errorf("Thresholds = %d, %d, want the NoiseFloor's %d, %d", start, stop, wantStart, wantStop)
+	}
+}
+
+// interruptSampler is a ReplaySampler interrupted after 'after' samples
+type interruptSampler struct {
+	ReplaySampler
+	after int
+}
+
+func (s *interruptSampler) Interrupted() bool { return s.Pos >= s.after }
+
+func TestCaptureVAD(t *testing.T) {
+	const sleepUs, preMs = 84, 1 // 100 us samples; 10 pre-trigger samples
+	pre := PreTriggerSamples(preMs, sleepUs)
+	if pre != 10 {
+		t.Fatalf("PreTriggerSamples = %d, want 10", pre)
+	}
+	// quiet samples carry their index in bits the VAD ignores, so the test can tell them apart
+	tag := func(s []uint16) []uint16 {
+		for i := range s {
+			s[i] += uint16(i % 16)
+		}
+		return s
+	}
+	word := tag(frames(quiet, quiet, quiet, quiet, quiet, quiet, quiet, quiet, quiet, quiet, // 40 samples
+		loud, loud, loud, loud, quiet, quiet, quiet, quiet))
+	early := tag(frames(quiet, loud, loud, loud, quiet, quiet, quiet))
+
+	tests := []struct {
+		name    string
+		stream  []uint16 // samples after the disposable first Get
+		bufSize int
+		first   int // stream index of buf[0]
+		want    Segment
+	}{
+		{"pre-trigger kept", word, 64, 30, Segment{Start: 10, End: 26, Reason: VADEnd}},
+		{"speech before a full pre-trigger", early, 64, 0, Segment{Start: 4, End: 16, Reason: VADEnd}},
+		{"buffer full", word, 20, 30, Segment{Start: 10, End: 20, Reason: VADFull}},
+		{"exhausted waiting", frames(quiet, quiet, quiet), 64, 0, Segment{Reason: VADNone}},
+	}
+	for _, tt := range tests {
+		s := NewReplaySampler(append([]uint16{0}, tt.stream...))
+		buf, seg := CaptureVAD(s, NullIndicator{}, tt.bufSize, sleepUs, preMs, testVAD)
+		if seg != tt.want {
+			t.Errorf("%s: segment %+v, want %+v", tt.name, seg, tt.want)
+		}
+		if seg.Reason == VADNone {
+			if len(buf) != 0 {
+				t.Errorf("%s: %d samples without speech, want none", tt.name, len(buf))
+			}
+			continue
+		}
+		if want := tt.stream[tt.first : tt.first+seg.End]; !reflect.DeepEqual(buf, want) {
+			t.Errorf("%s: buf is not stream[%d:%d]\n got %v\nwant %v", tt.name, tt.first, tt.first+seg.End, buf, want)
+		}
+	}
+}
+
+func TestCaptureVADInterrupted(t *testing.T) {
+	s := &interruptSampler{ReplaySampler: ReplaySampler{Samples: frames(quiet, quiet, quiet, quiet, loud, loud, loud)}, after: 8}
+	buf, seg := CaptureVAD(s, NullIndicator{}, 64, 84, 1, testVAD)
+	if len(buf) != 0 || seg.Reason != VADNone {
+		t.Errorf("interrupted while waiting: %d samples, %v, want none", len(buf), seg.Reason)
+	}
+	if s.Pos != 8 {
+		t.Errorf("interrupted after %d samples, want 8", s.Pos)
+	}
+}
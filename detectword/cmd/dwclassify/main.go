@@ -0,0 +1,321 @@
+// @file TinyGo/detectword/cmd/dwclassify/main.go
+// @date 2026.10.18
+// @info classify wav recordings the way the detectword_pico firmware would, without flashing
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+// @date 2026.10.18 N-way classification with match.ReduceWordDetectN; 2 to match.MaxRefWords -ref words
+// @date 2026.10.18 prints match.Detect outcome and confidence; -noisemargin, -minconf, -maxerr
+// @date 2026.10.18 multi-take references, -ref on=on1.wav,on2.wav,on3.wav, enrolled with match.EnrollWord
+// @date 2026.10.18 -ref on=on.dwt loads an enrolled template file; -savetemplates writes them
+// @date 2026.10.18 -metric dtw -band 1 compares with match.DTWErr instead of square error
+// @date 2026.10.18 -pretrigger ms kept before the capture threshold sample, adc.CaptureUint16Pre
+// @date 2026.10.18 captures by adc.CaptureVAD; -vadstart, -vadstop, -hangover, -minspeech
+// @date 2026.10.18 -adaptive; vad and noise thresholds follow an adc.NoiseFloor tracked across files
+// @date 2026.10.18 flags build a config.Config, validated before use, as the firmware's
+// @date 2026.10.18 -clocked resamples to the achieved rate of an adc fifo clocked capture, cfg.Clocked
+// @date 2026.10.18 -framelen 32 -hop 8 overlapping fft frames; Tbins derived as bufsize/hop
+// @date 2026.10.18 -spect real spectrogram by dsp.RealFFT, non-negative frequencies only
+// @date 2026.10.18 -spect q15, the fixed point spectrogram of dsp.CreateU16SpectQ15Frames
+// @date 2026.10.18 -spect mel, dsp.CreateU16MelFrames; -melfilters .. -deltas
+// @date 2026.10.18 -adaptive and -metric default to config.Default(), as the firmware; -adaptive=false
+//                  for the fixed thresholds
+// @date 2026.10.18 -framelen frames within -bufsize; tbins (bufsize-framelen)/hop+1
+// @date 2026.10.18 -melfilters 0 default, config.MelFilterCount()
+// @usage: dwclassify -ref on=on.wav -ref off=off.wav [-ref dim=dim.wav ...] test1.wav test2.wav ...
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/dsp"
+	"localhost/detectword/match"
+	"localhost/detectword/store"
+	"localhost/detectword/template"
+	"localhost/detectword/wav"
+)
+
+// noiseFloor is the -adaptive ambient noise estimate, shared by captures as on the pico
+var noiseFloor = adc.NewNoiseFloor(config.Default().Noise)
+
+// cfg is the Config built from the flags by configOf
+var cfg config.Config
+
+// refFlags collects repeated '-ref label=file.wav' flags
+type refFlags []string
+
+func (r *refFlags) String() string     { return strings.Join(*r, ",") }
+func (r *refFlags) Set(v string) error { *r = append(*r, v); return nil }
+
+// def holds the firmware --prod-- parameters, config.Default(); see detectword_pico.go main()
+var def = config.Default()
+
+var (
+	Tbins       = flag.Int("tbins", def.Tbins, "spectrogram time bins")
+	Fbins       = flag.Int("fbins", def.Fbins, "spectrogram frequency bins")
+	frameLen    = flag.Int("framelen", def.FrameLen, "fft frame points, overlapping frames; tbins is (bufsize-framelen)/hop+1, e.g. 64 for -bufsize 1040 -framelen 32 -hop 16. 0 is bufsize/tbins")
+	hop         = flag.Int("hop", def.Hop, "points between fft frame starts with -framelen; 0 is framelen")
+	spect       = flag.String("spect", dsp.SpectKind(def.Spect).String(), "spectrogram: complex, complex fft, real, real fft of the non-negative frequencies, q15, fixed point complex fft, or mel, mel filterbank energies, or MFCCs with -mfccs")
+	melFilters  = flag.Int("melfilters", def.MelFilters, "mel filters with -spect mel, at most framelen/2+1; 0 is 16, fewer for a short fft")
+	melLow      = flag.Int("mellow", def.MelLowHz, "mel filterbank low edge, Hz")
+	melHigh     = flag.Int("melhigh", def.MelHighHz, "mel filterbank high edge, Hz; 0 is fs/2")
+	mfccs       = flag.Int("mfccs", def.MFCCs, "MFCCs with -spect mel; 0 is log mel energies")
+	lifter      = flag.Int("lifter", def.Lifter, "MFCC lifter length; 0 none")
+	deltas      = flag.Int("deltas", def.Deltas, "MFCC delta orders appended, 0 to 2")
+	buf_size    = flag.Int("bufsize", def.BufSize, "capture buffer size in samples")
+	sleep_time  = flag.Int("sleep", def.SleepTime, "adc sleep time in us; sample period is sleep + get_us")
+	get_us      = flag.Int("getus", def.GetUs, "adc.Get() time in us")
+	SpectThresh = flag.Int("spectthresh", int(def.SpectThresh), "spectrogram noise threshold")
+	preTrigger  = flag.Int("pretrigger", def.PreTriggerMs, "ms kept before the capture threshold sample")
+	vadStart    = flag.Int("vadstart", def.VAD.StartEnergy, "vad frame energy starting speech")
+	vadStop     = flag.Int("vadstop", def.VAD.StopEnergy, "vad frame energy below which speech is quiet")
+	hangover    = flag.Int("hangover", def.VAD.HangoverFrames, "vad quiet frames before speech ends")
+	minSpeech   = flag.Int("minspeech", def.VAD.MinSpeechFrames, "vad frames before a start is speech, not a click")
+	adaptive    = flag.Bool("adaptive", def.AdaptNoise, "vad and noise thresholds relative to a noise floor tracked across files, in order")
+	clocked     = flag.Bool("clocked", def.Clocked, "adc fifo clocked capture at the achieved rate of sleep + getus, as cfg.Clocked")
+	minWordPct  = flag.Float64("minword", float64(def.MinWordLen)/float64(def.BufSize), "minimum word length as a fraction of bufsize")
+	vBlocks     = flag.Int("vblocks", def.VBlocks, "avg pool vertical block size")
+	hBlocks     = flag.Int("hblocks", def.HBlocks, "avg pool horizontal block size")
+	vBlocks2    = flag.Int("vblocks2", def.VBlocks2, "peak pool vertical block size")
+	hBlocks2    = flag.Int("hblocks2", def.HBlocks2, "peak pool horizontal block size")
+	gain        = flag.Float64("gain", 1.0, "gain applied to wav samples before adc scaling")
+	noiseMargin = flag.Int("noisemargin", def.NoiseMargin, "margin below is noise; 0 disables")
+	minConf     = flag.Float64("minconf", def.MinConfidence, "confidence below is ambiguous")
+	maxErr      = flag.Int("maxerr", def.MaxErr, "best word error above is nomatch; 0 disables")
+	metric      = flag.String("metric", match.Metric(def.Metric).String(), "reduction comparison: sq, square error, or dtw, dynamic time warping")
+	band        = flag.Int("band", def.DTWBand, "dtw band in reduced time rows")
+	keepTakes   = flag.Bool("keeptakes", def.KeepTakes, "keep each reference take for nearest neighbour matching, else average them")
+	noCapture   = flag.Bool("nocapture", false, "skip adc threshold gating and trimming; use the whole recording")
+	saveDir     = flag.String("savetemplates", "", "write each enrolled reference word to dir/<label>.dwt")
+)
+
+// configOf returns config.Default() with the flags applied
+func configOf() (c config.Config, err error) {
+	c = config.Default()
+	c.Tbins, c.Fbins, c.BufSize = *Tbins, *Fbins, *buf_size
+	c.FrameLen, c.Hop = *frameLen, *hop
+	c.DeriveTbins()
+	switch *spect {
+	case dsp.SpectComplex.String():
+		c.Spect = int(dsp.SpectComplex)
+	case dsp.SpectReal.String():
+		c.Spect = int(dsp.SpectReal)
+	case dsp.SpectQ15.String():
+		c.Spect = int(dsp.SpectQ15)
+	case dsp.SpectMel.String():
+		c.Spect = int(dsp.SpectMel)
+	default:
+		return c, fmt.Errorf("bad -spect %q; want complex, real, q15 or mel", *spect)
+	}
+	c.MelFilters, c.MelLowHz, c.MelHighHz = *melFilters, *melLow, *melHigh
+	c.MFCCs, c.Lifter, c.Deltas = *mfccs, *lifter, *deltas
+	c.SleepTime, c.GetUs, c.PreTriggerMs = *sleep_time, *get_us, *preTrigger
+	if *SpectThresh < 0 || *SpectThresh > 0xFFFF {
+		return c, &config.Error{Field: "SpectThresh", Value: *SpectThresh, Requirement: "a uint16"}
+	}
+	c.SpectThresh = uint16(*SpectThresh)
+	c.VAD.StartEnergy, c.VAD.StopEnergy = *vadStart, *vadStop
+	c.VAD.HangoverFrames, c.VAD.MinSpeechFrames = *hangover, *minSpeech
+	c.AdaptNoise = *adaptive
+	c.Clocked = *clocked
+	c.MinWordLen = int(*minWordPct * float64(*buf_size))
+	c.VBlocks, c.HBlocks, c.VBlocks2, c.HBlocks2 = *vBlocks, *hBlocks, *vBlocks2, *hBlocks2
+	c.NoiseMargin, c.MinConfidence, c.MaxErr = *noiseMargin, *minConf, *maxErr
+	switch *metric {
+	case match.SquareMetric.String():
+		c.Metric = int(match.SquareMetric)
+	case match.DTWMetric.String():
+		c.Metric = int(match.DTWMetric)
+	default:
+		return c, fmt.Errorf("bad -metric %q; want sq or dtw", *metric)
+	}
+	c.DTWBand = *band
+	c.KeepTakes = *keepTakes
+	return c, c.Validate()
+}
+
+func main() {
+	var refs refFlags
+	flag.Var(&refs, "ref", "reference word as label=file.wav[,take2.wav,...] or label=file.dwt; repeat for each word")
+	flag.Parse()
+	if len(refs) < 2 || len(refs) > match.MaxRefWords || flag.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "usage: dwclassify -ref on=a.wav -ref off=b.wav [-ref ...] [flags] test.wav ...\n")
+		fmt.Fprintf(os.Stderr, "2 to %d reference words\n", match.MaxRefWords)
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	var err error
+	if cfg, err = configOf(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	HammingFftPoints := dsp.Hamming(cfg.FftPoints())
+	prm := store.ParamsOf(cfg)
+
+	words := make([]match.RefWord, len(refs))
+	for i, r := range refs {
+		kv := strings.SplitN(r, "=", 2)
+		if len(kv) != 2 {
+			fmt.Fprintf(os.Stderr, "bad -ref %q; want label=file.wav\n", r)
+			os.Exit(2)
+		}
+		if strings.HasSuffix(kv[1], template.Ext) {
+			t, err := template.ReadFile(kv[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", kv[1], err)
+				os.Exit(1)
+			}
+			if t.Params != prm {
+				fmt.Fprintf(os.Stderr, "%s: template params %+v differ from %+v\n", kv[1], t.Params, prm)
+				os.Exit(1)
+			}
+			words[i] = t.RefWord()
+			words[i].Label = kv[0]
+			continue
+		}
+		enroll := match.EnrollParamsOf(cfg)
+		var takes [][][]int
+		files := strings.Split(kv[1], ",")
+		for _, takeFile := range files {
+			uBuf, err := loadCapture(takeFile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			if len(uBuf) == 0 || len(uBuf) < cfg.MinWordLen {
+				fmt.Fprintf(os.Stderr, "reference %s take %s is too short or too quiet\n", kv[0], takeFile)
+				continue
+			}
+			reduced, _, _, bIsNoise := match.EnrollTake(uBuf, HammingFftPoints, noiseThresh(), enroll)
+			if bIsNoise {
+				fmt.Fprintf(os.Stderr, "reference %s take %s is noise or too quiet\n", kv[0], takeFile)
+				continue
+			}
+			takes = append(takes, reduced)
+		}
+		if len(files) < enroll.MinAccepted {
+			enroll.MinAccepted = len(files)
+		}
+		word, rejected, err := match.EnrollWord(kv[0], takes, enroll)
+		for _, k := range rejected {
+			fmt.Fprintf(os.Stderr, "reference %s take %d rejected\n", kv[0], k)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "reference %s: %v\n", kv[0], err)
+			os.Exit(1)
+		}
+		words[i] = word
+		if *saveDir != "" {
+			filename := filepath.Join(*saveDir, word.Label+template.Ext)
+			if err = template.WriteFile(filename, template.New(prm, word, nil)); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	fmt.Printf("%-32s %-9s %-8s %8s %8s %6s", "file", "outcome", "word", "err", "margin", "conf")
+	for _, w := range words {
+		fmt.Printf(" %8s", w.Label)
+	}
+	if len(words) == 2 {
+		fmt.Printf(" %8s", "lse-dse")
+	}
+	fmt.Printf("\n")
+	exit := 0
+	for _, filename := range flag.Args() {
+		uBuf, err := loadCapture(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit = 1
+			continue
+		}
+		if len(uBuf) < cfg.MinWordLen {
+			fmt.Printf("%-32s %-9s\n", filename, "short")
+			continue
+		}
+		U16Spect, bIsNoise := dsp.CreateU16SpectConfig(uBuf, HammingFftPoints, cfg, noiseThresh())
+		d := match.DetectConfig(U16Spect, bIsNoise, words, cfg)
+		if d.Outcome == match.Noise && d.Errs == nil {
+			fmt.Printf("%-32s %-9s\n", filename, d.Outcome)
+			continue
+		}
+		word := "-" // best word, match or not
+		if d.Best != match.NoWord {
+			word = words[d.Best].Label
+		}
+		fmt.Printf("%-32s %-9s %-8s %8d %8d %6.3f", filename, d.Outcome, word, d.Err, d.Margin, d.Confidence)
+		for _, e := range d.Errs {
+			fmt.Printf(" %8d", e)
+		}
+		if len(d.Errs) == 2 { // ReduceWordDetect lse-dse
+			fmt.Printf(" %8d", d.Errs[0]-d.Errs[1])
+		}
+		fmt.Printf("\n")
+	}
+	os.Exit(exit)
+} // end func main
+
+// loadCapture reads 'filename', converts it to pico adc samples at the pico sample rate,
+// and unless -nocapture runs it through the firmware capture loop, adc.CaptureVAD
+func loadCapture(filename string) (uBuf []uint16, err error) {
+	pcm, rate, err := wav.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	picoRate := cfg.SampleRate() // e.g. (/ 1.0 266e-6) 3759 samp/sec
+	uBuf = dsp.ResampleUint16(wav.Pcm2Adc(applyGain(pcm, *gain), wav.AdcMid), float64(rate), picoRate)
+	if *noCapture {
+		if len(uBuf) > cfg.BufSize {
+			uBuf = uBuf[:cfg.BufSize]
+		}
+		return uBuf, nil
+	}
+	vp := cfg.VAD
+	if cfg.AdaptNoise {
+		vp.Noise = noiseFloor
+	}
+	var sampler adc.Sampler = adc.NewReplaySampler(uBuf)
+	if cfg.Clocked {
+		sampler = adc.NewClockedSampler(sampler, picoRate)
+	}
+	uBuf, _ = adc.CaptureVAD(sampler, adc.NullIndicator{}, cfg.BufSize, cfg.SleepTime, cfg.PreTriggerMs, vp)
+	return uBuf, nil
+}
+
+// noiseThresh returns the capture noise threshold; relative to noiseFloor with -adaptive
+func noiseThresh() uint16 {
+	if cfg.AdaptNoise {
+		return noiseFloor.PeakThreshold(cfg.VAD.DC)
+	}
+	return dsp.NoiseThreshold
+}
+
+// applyGain scales 'pcm' by 'gain', clipping at the int16 range
+func applyGain(pcm []int16, gain float64) []int16 {
+	if gain == 1.0 {
+		return pcm
+	}
+	out := make([]int16, len(pcm))
+	for i, v := range pcm {
+		f := float64(v) * gain
+		if f < -32768 {
+			f = -32768
+		}
+		if f > 32767 {
+			f = 32767
+		}
+		out[i] = int16(f)
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+// @file TinyGo/detectword/cmd/dweval/main.go
+// @date 2026.10.18
+// @info evaluate detection over a labelled recording set; replays the recordings through the
+//       firmware capture, spectrogram, reduction and match.DetectConfig decision, and reports
+//       a confusion matrix, per word precision and recall, and ROC/DET points over the margin
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+// @usage: dweval [-config best.cfg] [-csv out/eval] [-v] set
+//         set/on/*.wav, set/off/*.wav, ... one directory per word, the first Takes of each
+//         enrolled; set/_noise/*.wav, set/_other/*.wav negatives; as dwtune
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"localhost/detectword/config"
+	"localhost/detectword/eval"
+)
+
+func main() {
+	base := flag.String("config", "", "Config file, e.g. written by dwtune; default config.Default()")
+	gain := flag.Float64("gain", 1.0, "gain applied to wav samples before adc scaling")
+	noCapture := flag.Bool("nocapture", false, "skip adc.CaptureVAD gating and trimming; use the whole recording")
+	csvPrefix := flag.String("csv", "", "also write <prefix>_confusion.csv, _classes.csv, _roc.csv and _trials.csv")
+	verbose := flag.Bool("v", false, "list each trial")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "usage: dweval [flags] set\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	cfg := config.Default()
+	if *base != "" {
+		var err error
+		if cfg, err = config.ReadFile(*base); err != nil {
+			fmt.Fprintln(os.Stderr, "dweval:", err)
+			os.Exit(2)
+		}
+	}
+	set, err := eval.LoadDir(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dweval:", err)
+		os.Exit(1)
+	}
+	set.Gain, set.NoGate = *gain, *noCapture
+	set.Capture(cfg)
+	r, err := eval.Run(set, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dweval:", err)
+		os.Exit(1)
+	}
+
+	s := r.Score()
+	fmt.Printf("%s: %d words, %d recordings, %d enrolled, %d trials\n", set.Dir, len(set.Words),
+		len(set.Items), len(r.Enrolled), s.Trials)
+	fmt.Printf("accuracy %.3f, false accept %.3f, false reject %.3f\n\n", s.Accuracy(), s.FARate(), s.FRRate())
+	conf := r.Confusion()
+	tables := map[string]eval.Table{
+		"confusion": conf.Table(),
+		"classes":   eval.ClassTable(conf.Classes()),
+		"roc":       eval.ROCTable(r.ROC()),
+		"trials":    r.TrialTable(),
+	}
+	order := []string{"confusion", "classes", "roc", "trials"}
+	for _, name := range order {
+		if name == "trials" && !*verbose {
+			continue
+		}
+		tables[name].WriteText(os.Stdout)
+		fmt.Println()
+	}
+	if *csvPrefix == "" {
+		return
+	}
+	for _, name := range order {
+		filename := *csvPrefix + "_" + name + ".csv"
+		if err := writeCSV(filename, tables[name]); err != nil {
+			fmt.Fprintln(os.Stderr, "dweval:", err)
+			os.Exit(1)
+		}
+		fmt.Println(filename)
+	}
+} // end func main
+
+// writeCSV writes 't' to 'filename'
+func writeCSV(filename string, t eval.Table) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err = t.WriteCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,205 @@
+// @file TinyGo/detectword/cmd/dwplot/main.go
+// @date 2026.10.18
+// @info plot a capture's time waveform, spectrogram, and avg and peak pooled arrays as png
+//       images, the write up figures without GNU Octave; axes in ms and Hz from the sample
+//       period of the params
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 -framelen, -hop overlapping fft frames, as dwclassify
+// @date 2026.10.18 -spect real; frequency axis 0 to fs/2. -spect q15
+// @date 2026.10.18 -spect mel, -melfilters .. -deltas; mel filter or MFCC axis
+// @date 2026.10.18 -framelen frames within -bufsize; tbins (bufsize-framelen)/hop+1
+// @date 2026.10.18 -melfilters 0 default, config.MelFilterCount()
+
+// @build: go build
+// @usage: dwplot [-o on] on.wav          writes on_xt.png, on_spect.png, on_pool1.png, on_pool2.png
+//         dwplot [-o cap] file00_xt.dat  a dwuart or uart_xfr capture, decimal samples
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/dsp"
+	"localhost/detectword/match"
+	"localhost/detectword/plot"
+	"localhost/detectword/wav"
+)
+
+var def = config.Default()
+
+var (
+	Tbins       = flag.Int("tbins", def.Tbins, "spectrogram time bins")
+	Fbins       = flag.Int("fbins", def.Fbins, "spectrogram frequency bins")
+	frameLen    = flag.Int("framelen", def.FrameLen, "fft frame points, overlapping frames; tbins is (bufsize-framelen)/hop+1, e.g. 64 for -bufsize 1040 -framelen 32 -hop 16. 0 is bufsize/tbins")
+	hop         = flag.Int("hop", def.Hop, "points between fft frame starts with -framelen; 0 is framelen")
+	spect       = flag.String("spect", dsp.SpectKind(def.Spect).String(), "spectrogram: complex, complex fft, real, real fft of the non-negative frequencies, q15, fixed point complex fft, or mel, mel filterbank energies, or MFCCs with -mfccs")
+	melFilters  = flag.Int("melfilters", def.MelFilters, "mel filters with -spect mel, at most framelen/2+1; 0 is 16, fewer for a short fft")
+	melLow      = flag.Int("mellow", def.MelLowHz, "mel filterbank low edge, Hz")
+	melHigh     = flag.Int("melhigh", def.MelHighHz, "mel filterbank high edge, Hz; 0 is fs/2")
+	mfccs       = flag.Int("mfccs", def.MFCCs, "MFCCs with -spect mel; 0 is log mel energies")
+	lifter      = flag.Int("lifter", def.Lifter, "MFCC lifter length; 0 none")
+	deltas      = flag.Int("deltas", def.Deltas, "MFCC delta orders appended, 0 to 2")
+	buf_size    = flag.Int("bufsize", def.BufSize, "capture buffer size in samples")
+	sleep_time  = flag.Int("sleep", def.SleepTime, "adc sleep time in us; sample period is sleep + get_us")
+	get_us      = flag.Int("getus", def.GetUs, "adc.Get() time in us")
+	SpectThresh = flag.Int("spectthresh", int(def.SpectThresh), "spectrogram noise threshold")
+	vBlocks     = flag.Int("vblocks", def.VBlocks, "avg pool vertical block size")
+	hBlocks     = flag.Int("hblocks", def.HBlocks, "avg pool horizontal block size")
+	vBlocks2    = flag.Int("vblocks2", def.VBlocks2, "peak pool vertical block size")
+	hBlocks2    = flag.Int("hblocks2", def.HBlocks2, "peak pool horizontal block size")
+	gain        = flag.Float64("gain", 1.0, "gain applied to wav samples before adc scaling")
+	noCapture   = flag.Bool("nocapture", false, "skip adc.CaptureVAD gating and trimming of a wav; use the whole recording")
+	base        = flag.Int("base", 10, "number base of .dat samples, 10 or 16")
+	out         = flag.String("o", "", "output file prefix; default the input name without extension")
+)
+
+// cfg is the Config built from the flags by configOf
+var cfg config.Config
+
+func configOf() (c config.Config, err error) {
+	c = config.Default()
+	c.Tbins, c.Fbins, c.BufSize = *Tbins, *Fbins, *buf_size
+	c.FrameLen, c.Hop = *frameLen, *hop
+	c.DeriveTbins()
+	switch *spect {
+	case dsp.SpectComplex.String():
+		c.Spect = int(dsp.SpectComplex)
+	case dsp.SpectReal.String():
+		c.Spect = int(dsp.SpectReal)
+	case dsp.SpectQ15.String():
+		c.Spect = int(dsp.SpectQ15)
+	case dsp.SpectMel.String():
+		c.Spect = int(dsp.SpectMel)
+	default:
+		return c, fmt.Errorf("bad -spect %q; want complex, real, q15 or mel", *spect)
+	}
+	c.MelFilters, c.MelLowHz, c.MelHighHz = *melFilters, *melLow, *melHigh
+	c.MFCCs, c.Lifter, c.Deltas = *mfccs, *lifter, *deltas
+	c.SleepTime, c.GetUs = *sleep_time, *get_us
+	if *SpectThresh < 0 || *SpectThresh > 0xFFFF {
+		return c, &config.Error{Field: "SpectThresh", Value: *SpectThresh, Requirement: "a uint16"}
+	}
+	c.SpectThresh = uint16(*SpectThresh)
+	c.VBlocks, c.HBlocks, c.VBlocks2, c.HBlocks2 = *vBlocks, *hBlocks, *vBlocks2, *hBlocks2
+	return c, c.Validate()
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "usage: dwplot [flags] capture.wav | file00_xt.dat\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	var err error
+	if cfg, err = configOf(); err != nil {
+		fmt.Fprintln(os.Stderr, "dwplot:", err)
+		os.Exit(2)
+	}
+	filename := flag.Arg(0)
+	prefix := *out
+	if prefix == "" {
+		prefix = strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+	uBuf, err := loadCapture(filename)
+	if err == nil && len(uBuf) == 0 {
+		err = fmt.Errorf("%s: no samples captured", filename)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dwplot:", err)
+		os.Exit(1)
+	}
+
+	fs := cfg.SampleRate()
+	tMs := float64(len(uBuf)) * 1000 / fs // the spectrogram stretches the capture to BufSize
+	name := filepath.Base(filename)
+	timeAxis := plot.Axis{Min: 0, Max: tMs, Label: "time ms"}
+	lo, hi := dsp.SpectKind(cfg.Spect).FreqRange() // dsp.FftLogShift order, or DC first
+	freqAxis := plot.Axis{Min: lo * fs, Max: hi * fs, Label: "frequency Hz"}
+	if mel := dsp.MelParamsOf(cfg); cfg.Spect == int(dsp.SpectMel) { // cols resized from mel.Cols()
+		freqAxis = plot.Axis{Min: 0, Max: float64(mel.Cols()), Label: "mel filter"}
+		if mel.MFCCs > 0 {
+			freqAxis.Label = "mfcc, then deltas"
+		}
+	}
+
+	volts := make([]float64, len(uBuf))
+	for i, v := range uBuf {
+		volts[i] = float64(v) * 3.3 / 65536
+	}
+	U16Spect, _ := dsp.CreateU16SpectConfig(uBuf, dsp.Hamming(cfg.FftPoints()), cfg, dsp.NoiseThreshold)
+	pool2, pool1 := match.ReduceConfig(U16Spect, cfg)
+	images := []struct {
+		kind string
+		img  image.Image
+	}{
+		{"xt", plot.Line(volts, timeAxis, plot.Axis{Min: 0, Max: 3.3, Label: "adc V"}, name+" waveform")},
+		{"spect", plot.Heatmap(intsOf(U16Spect), timeAxis, freqAxis, name+" spectrogram")},
+		{"pool1", plot.Heatmap(pool1, timeAxis, freqAxis, name+" avg pool")},
+		{"pool2", plot.Heatmap(pool2, timeAxis, freqAxis, name+" peak pool")},
+	}
+	for _, im := range images {
+		filename := prefix + "_" + im.kind + ".png"
+		if err := plot.WritePNG(filename, im.img); err != nil {
+			fmt.Fprintln(os.Stderr, "dwplot:", err)
+			os.Exit(1)
+		}
+		fmt.Println(filename)
+	}
+} // end func main
+
+// loadCapture reads a .dat sample file as captured, or a wav converted to pico adc samples
+// at the pico sample rate and, unless -nocapture, run through adc.CaptureVAD as dwclassify
+func loadCapture(filename string) (uBuf []uint16, err error) {
+	if strings.EqualFold(filepath.Ext(filename), ".dat") {
+		return adc.ReadSampleFile(filename, *base)
+	}
+	pcm, rate, err := wav.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if *gain != 1.0 {
+		for i, v := range pcm {
+			f := float64(v) * *gain
+			if f < -32768 {
+				f = -32768
+			}
+			if f > 32767 {
+				f = 32767
+			}
+			pcm[i] = int16(f)
+		}
+	}
+	uBuf = dsp.ResampleUint16(wav.Pcm2Adc(pcm, wav.AdcMid), float64(rate), cfg.SampleRate())
+	if *noCapture {
+		if len(uBuf) > cfg.BufSize {
+			uBuf = uBuf[:cfg.BufSize]
+		}
+		return uBuf, nil
+	}
+	uBuf, _ = adc.CaptureVAD(adc.NewReplaySampler(uBuf), adc.NullIndicator{}, cfg.BufSize, cfg.SleepTime, cfg.PreTriggerMs, cfg.VAD)
+	return uBuf, nil
+}
+
+// intsOf converts a spectrogram to the [][]int of plot.Heatmap
+func intsOf(u [][]uint16) [][]int {
+	m := make([][]int, len(u))
+	for i, r := range u {
+		m[i] = make([]int, len(r))
+		for j, v := range r {
+			m[i][j] = int(v)
+		}
+	}
+	return m
+}
@@ -0,0 +1,228 @@
+// @file TinyGo/detectword/cmd/dwq15/main.go
+// @date 2026.10.18
+// @info validate the Q15 fixed point spectrogram pipeline against the float one on the host;
+//       Q15FFT against FFT, MagnitudeQ15 and Log2Q8 against math, and over a recording set
+//       CreateU16SpectQ15Frames against CreateU16SpectFrames and their detections
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+// @usage: dwq15 [-tol 1] [-within 0.99] [set]
+//         exits 1 when an fft is below -snr, or fewer than -within of the spectrogram bins
+//         above SpectThresh are within -tol dB of the float path
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"os"
+
+	"localhost/detectword/config"
+	"localhost/detectword/dsp"
+	"localhost/detectword/eval"
+)
+
+var (
+	base      = flag.String("config", "", "Config file of the set comparison; default config.Default(), Spect ignored")
+	gain      = flag.Float64("gain", 1.0, "gain applied to wav samples before adc scaling")
+	tol       = flag.Float64("tol", 1, "spectrogram tolerance, dB")
+	within    = flag.Float64("within", 0.99, "fraction of spectrogram bins above SpectThresh required within -tol")
+	minSNR    = flag.Float64("snr", 40, "minimum Q15FFT signal to error ratio, dB, of full scale and -20 dB frames")
+	seed      = flag.Int64("seed", 1, "random frame seed")
+	failed    = false
+	fftLevels = []float64{0, -20, -40} // dB below full scale
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() > 1 {
+		fmt.Fprintf(os.Stderr, "usage: dwq15 [flags] [set]\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	checkFFT()
+	checkMagnitudeLog()
+	if flag.NArg() == 1 {
+		checkSet(flag.Arg(0))
+	}
+	if failed {
+		fmt.Println("FAIL")
+		os.Exit(1)
+	}
+	fmt.Println("ok")
+} // end func main
+
+// checkFFT compares Q15FFT x 2^exp to FFT of noise and sine frames of each size and level
+func checkFFT() {
+	rnd := rand.New(rand.NewSource(*seed))
+	fmt.Printf("Q15FFT signal to error ratio, dB, by frame level below full scale\n%6s", "points")
+	for _, l := range fftLevels {
+		fmt.Printf(" %7s %7s", fmt.Sprintf("noise%g", l), fmt.Sprintf("sine%g", l))
+	}
+	fmt.Println()
+	for n := 16; n <= dsp.Q15MaxLen; n <<= 1 {
+		fmt.Printf("%6d", n)
+		for _, l := range fftLevels {
+			amp := math.Pow(10, l/20)
+			noise := make([]float64, n)
+			sine := make([]float64, n)
+			for i := range noise {
+				noise[i] = amp * (rnd.Float64()*2 - 1)
+				sine[i] = amp * math.Sin(2*math.Pi*float64(i)*3.3/float64(n))
+			}
+			for _, x := range [][]float64{noise, sine} {
+				snr := fftSNR(x)
+				if l > -40 && snr < *minSNR {
+					failed = true
+				}
+				fmt.Printf(" %7.1f", snr)
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// fftSNR returns the signal to error ratio of Q15FFT of 'x', -1 to 1, to FFT of its Q15 values
+func fftSNR(x []float64) float64 {
+	n := len(x)
+	re, im := make([]int16, n), make([]int16, n)
+	c := make([]complex128, n)
+	for i, v := range x {
+		re[i] = dsp.Q15(v)
+		c[i] = complex(float64(re[i]), 0)
+	}
+	exp, err := dsp.Q15FFT(re, im)
+	if err != nil {
+		panic(err)
+	}
+	if err = dsp.FFT(c); err != nil {
+		panic(err)
+	}
+	scale := math.Ldexp(1, exp)
+	sig, e := 0.0, 0.0
+	for i := range c {
+		d := c[i] - complex(float64(re[i])*scale, float64(im[i])*scale)
+		sig += real(c[i])*real(c[i]) + imag(c[i])*imag(c[i])
+		e += real(d)*real(d) + imag(d)*imag(d)
+	}
+	if e == 0 {
+		return math.Inf(1)
+	}
+	return 10 * math.Log10(sig/e)
+}
+
+// checkMagnitudeLog reports the worst dB error of MagnitudeQ15, Log2Q8 and both, over
+// every angle and a range of magnitudes
+func checkMagnitudeLog() {
+	worstMag, worstLog, worstDb := 0.0, 0.0, 0.0
+	for r := 64.0; r < 20000; r *= 1.07 {
+		for a := 0.0; a < math.Pi/2; a += math.Pi / 180 {
+			re, im := int16(r*math.Cos(a)), int16(r*math.Sin(a))
+			exact := cmplx.Abs(complex(float64(re), float64(im)))
+			mag := dsp.MagnitudeQ15(re, im)
+			worstMag = math.Max(worstMag, math.Abs(20*math.Log10(float64(mag)/exact)))
+			worstLog = math.Max(worstLog, math.Abs(float64(dsp.Log2Q8(mag))/256-math.Log2(float64(mag)))*20*math.Log10(2))
+			db := float64(dsp.Log2Q8ToDb(dsp.Log2Q8(mag)))
+			worstDb = math.Max(worstDb, math.Abs(db-20*math.Log10(exact)))
+		}
+	}
+	fmt.Printf("MagnitudeQ15 worst %.2f dB, Log2Q8 worst %.2f dB, dB with whole dB truncation worst %.2f dB\n\n",
+		worstMag, worstLog, worstDb)
+}
+
+// checkSet compares the Q15 and float spectrograms of each capture of set 'dir', and the
+// detections of each
+func checkSet(dir string) {
+	cfg := config.Default()
+	if *base != "" {
+		var err error
+		if cfg, err = config.ReadFile(*base); err != nil {
+			fmt.Fprintln(os.Stderr, "dwq15:", err)
+			os.Exit(2)
+		}
+	}
+	cfg.Spect = int(dsp.SpectComplex)
+	q15 := cfg
+	q15.Spect = int(dsp.SpectQ15)
+	if err := q15.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "dwq15:", err)
+		os.Exit(2)
+	}
+	set, err := eval.LoadDir(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dwq15:", err)
+		os.Exit(1)
+	}
+	set.Gain = *gain
+	set.Capture(cfg)
+
+	hamming := dsp.Hamming(cfg.FftPoints())
+	bins, in, worst, sum := 0, 0, 0, 0
+	for _, it := range set.Items {
+		if len(it.Capture) < cfg.MinWordLen {
+			continue
+		}
+		f, fNoise := dsp.CreateU16SpectConfig(it.Capture, hamming, cfg, it.NoiseThresh)
+		q, qNoise := dsp.CreateU16SpectConfig(it.Capture, hamming, q15, it.NoiseThresh)
+		if fNoise != qNoise {
+			fmt.Printf("%s: noise %v float, %v q15\n", it.Path, fNoise, qNoise)
+			failed = true
+			continue
+		}
+		for i := range f {
+			for j := range f[i] {
+				if f[i][j] <= cfg.SpectThresh && q[i][j] <= cfg.SpectThresh {
+					continue
+				}
+				d := int(f[i][j]) - int(q[i][j])
+				if d < 0 {
+					d = -d
+				}
+				bins++
+				sum += d
+				if d > worst {
+					worst = d
+				}
+				if float64(d) <= *tol {
+					in++
+				}
+			}
+		}
+	}
+	frac := 0.0
+	if bins > 0 {
+		frac = float64(in) / float64(bins)
+	}
+	fmt.Printf("%s: %d spectrogram bins above SpectThresh %d; %.4f within %g dB, mean %.3f dB, worst %d dB\n",
+		set.Dir, bins, cfg.SpectThresh, frac, *tol, float64(sum)/math.Max(1, float64(bins)), worst)
+	if frac < *within {
+		failed = true
+	}
+
+	rf, err := eval.Run(set, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dwq15: float:", err)
+		os.Exit(1)
+	}
+	rq, err := eval.Run(set, q15)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dwq15: q15:", err)
+		os.Exit(1)
+	}
+	same := 0
+	for i := range rf.Trials {
+		if rf.Trials[i].D.Outcome == rq.Trials[i].D.Outcome && rf.Trials[i].D.Word == rq.Trials[i].D.Word {
+			same++
+		}
+	}
+	sf, sq := rf.Score(), rq.Score()
+	fmt.Printf("detection: float accuracy %.3f, q15 accuracy %.3f; %d of %d trials decided alike\n",
+		sf.Accuracy(), sq.Accuracy(), same, len(rf.Trials))
+} // end func checkSet
@@ -0,0 +1,226 @@
+// @file TinyGo/detectword/cmd/dwtemplate/main.go
+// @date 2026.10.18
+// @info dump, diff and convert reference word template files; extract templates from, and
+//       pack them into, store flash images
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+// @usage: dwtemplate dump on.dwt
+//         dwtemplate diff on.dwt on_board2.dwt
+//         dwtemplate json on.dwt > on.json; dwtemplate fromjson on.json on.dwt
+//         dwtemplate extract flash.img outdir; dwtemplate pack flash.img on.dwt off.dwt
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"localhost/detectword/match"
+	"localhost/detectword/store"
+	"localhost/detectword/template"
+)
+
+const usage = `usage: dwtemplate <command> args
+  dump file.dwt ...              print params, label and matrices
+  diff a.dwt b.dwt               print param differences and matrix errors
+  json file.dwt                  write json to stdout
+  fromjson file.json file.dwt    convert json to a template file
+  extract flash.img dir          write each word of a store record as dir/<label>.dwt
+  pack flash.img file.dwt ...    save templates, in order, as a store record
+`
+
+// flashSize of images pack creates; the firmware reads only the record at offset 0
+const flashSize = 16 * store.PicoEraseBlockSize
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	args := os.Args[2:]
+	var err error
+	switch os.Args[1] {
+	case "dump":
+		err = dump(args)
+	case "diff":
+		if len(args) != 2 {
+			err = fmt.Errorf("diff takes 2 files")
+			break
+		}
+		err = diff(args[0], args[1])
+	case "json":
+		var t template.Template
+		if t, err = template.ReadFile(args[0]); err == nil {
+			err = template.WriteJSON(os.Stdout, t)
+		}
+	case "fromjson":
+		if len(args) != 2 {
+			err = fmt.Errorf("fromjson takes a json file and a template file")
+			break
+		}
+		err = fromJSON(args[0], args[1])
+	case "extract":
+		if len(args) != 2 {
+			err = fmt.Errorf("extract takes a flash image and a directory")
+			break
+		}
+		err = extract(args[0], args[1])
+	case "pack":
+		err = pack(args[0], args[1:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dwtemplate:", err)
+		os.Exit(1)
+	}
+} // end func main
+
+// dump prints each template file in 'files'
+func dump(files []string) error {
+	for _, filename := range files {
+		t, err := template.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("%s: %v", filename, err)
+		}
+		fmt.Printf("%s: label %q\n", filename, t.Label)
+		fmt.Printf("params %+v\n", t.Params)
+		printMatrix("reduced", t.Reduced)
+		for k, take := range t.Takes {
+			printMatrix(fmt.Sprintf("take %d", k), take)
+		}
+		if t.PoolAvg != nil {
+			printMatrix("pool avg", t.PoolAvg)
+		}
+	}
+	return nil
+}
+
+// diff compares templates 'fileA' and 'fileB' as the detector would: squared error of
+// the reduced matrices, then the per cell difference
+func diff(fileA, fileB string) error {
+	a, err := template.ReadFile(fileA)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fileA, err)
+	}
+	b, err := template.ReadFile(fileB)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fileB, err)
+	}
+	if a.Label != b.Label {
+		fmt.Printf("label %q != %q\n", a.Label, b.Label)
+	}
+	if a.Params != b.Params {
+		fmt.Printf("params differ; templates will not match the same captures\n< %+v\n> %+v\n", a.Params, b.Params)
+	}
+	if !sameShape(a.Reduced, b.Reduced) {
+		return fmt.Errorf("reduced matrices %s and %s differ in shape", shape(a.Reduced), shape(b.Reduced))
+	}
+	fmt.Printf("reduced square error %d\n", match.SquareErr(a.Reduced, b.Reduced))
+	printMatrix("reduced a-b", subMatrix(a.Reduced, b.Reduced))
+	if a.PoolAvg != nil && b.PoolAvg != nil && sameShape(a.PoolAvg, b.PoolAvg) {
+		fmt.Printf("pool avg square error %d\n", match.SquareErr(a.PoolAvg, b.PoolAvg))
+	}
+	return nil
+}
+
+// fromJSON converts json 'jsonFile' to template file 'filename'
+func fromJSON(jsonFile, filename string) error {
+	f, err := os.Open(jsonFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	t, err := template.ReadJSON(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", jsonFile, err)
+	}
+	return template.WriteFile(filename, t)
+}
+
+// extract writes each word of the store record in flash image 'image' to 'dir'
+func extract(image, dir string) error {
+	if _, err := os.Stat(image); err != nil {
+		return err
+	}
+	dev, err := store.OpenFileDevice(image, 0)
+	if err != nil {
+		return err
+	}
+	rec, err := store.Load(dev)
+	if err != nil {
+		return fmt.Errorf("%s: %v", image, err)
+	}
+	for _, ref := range rec.Refs {
+		filename := filepath.Join(dir, ref.Label+template.Ext)
+		if err = template.WriteFile(filename, template.New(rec.Params, ref, nil)); err != nil {
+			return err
+		}
+		fmt.Println(filename)
+	}
+	return nil
+}
+
+// pack saves template 'files' as one store record to flash image 'image'; all templates
+// must have equal Params
+func pack(image string, files []string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("pack takes a flash image and template files")
+	}
+	var rec store.Record
+	for i, filename := range files {
+		t, err := template.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("%s: %v", filename, err)
+		}
+		if i == 0 {
+			rec.Params = t.Params
+		} else if t.Params != rec.Params {
+			return fmt.Errorf("%s: params %+v differ from %s", filename, t.Params, files[0])
+		}
+		rec.Refs = append(rec.Refs, t.RefWord())
+	}
+	dev, err := store.OpenFileDevice(image, flashSize)
+	if err != nil {
+		return err
+	}
+	return store.Save(dev, rec)
+}
+
+func printMatrix(name string, m [][]int) {
+	fmt.Printf("%s %s\n", name, shape(m))
+	for _, row := range m {
+		for _, v := range row {
+			fmt.Printf(" %6d", v)
+		}
+		fmt.Printf("\n")
+	}
+}
+
+func shape(m [][]int) string {
+	if len(m) == 0 {
+		return "[0x0]"
+	}
+	return fmt.Sprintf("[%dx%d]", len(m), len(m[0]))
+}
+
+func sameShape(a, b [][]int) bool {
+	return len(a) == len(b) && (len(a) == 0 || len(a[0]) == len(b[0]))
+}
+
+func subMatrix(a, b [][]int) [][]int {
+	d := make([][]int, len(a))
+	for i := range a {
+		d[i] = make([]int, len(a[i]))
+		for j := range a[i] {
+			d[i][j] = a[i][j] - b[i][j]
+		}
+	}
+	return d
+}
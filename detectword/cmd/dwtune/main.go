@@ -0,0 +1,264 @@
+// @file TinyGo/detectword/cmd/dwtune/main.go
+// @date 2026.10.18
+// @info sweep spectrogram, reduction and decision params across a grid over a labelled
+//       recording set, in place of hand tuning (Fbins 64->32, SpectThresh 50->60, delta
+//       thresholds 250->400); reports accuracy, false accept and false reject rates and
+//       writes the best Config file
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 -framelen, -hop sweep overlapping fft frames; Tbins derived from them
+// @date 2026.10.18 -noisemargin 0,50,100; a margin below NoiseMargin is noise
+// @date 2026.10.18 -noisemargin 0,100,400; 400 the config.Default() NoiseMargin
+// @date 2026.10.18 -framelen Tbins (BufSize-FrameLen)/Hop+1; -config sets a BufSize of whole frames
+
+// @build: go build
+// @usage: dwtune [-config base.cfg] [-tbins 32,64] [-fbins 32,64] [-spectthresh 50,60,70] ... \
+//         [-o best.cfg] set
+//         set/on/*.wav, set/off/*.wav, ... one directory per word, the first Takes of each
+//         enrolled; set/_noise/*.wav, set/_other/*.wav negatives
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"localhost/detectword/config"
+	"localhost/detectword/eval"
+)
+
+// intList is a comma separated list flag of ints
+type intList []int
+
+func (l *intList) String() string {
+	s := make([]string, len(*l))
+	for i, v := range *l {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *intList) Set(v string) error {
+	*l = nil
+	for _, f := range strings.Split(v, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return err
+		}
+		*l = append(*l, n)
+	}
+	return nil
+}
+
+// floatList is a comma separated list flag of floats
+type floatList []float64
+
+func (l *floatList) String() string {
+	s := make([]string, len(*l))
+	for i, v := range *l {
+		s[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *floatList) Set(v string) error {
+	*l = nil
+	for _, f := range strings.Split(v, ",") {
+		n, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, n)
+	}
+	return nil
+}
+
+var (
+	tbins       = intList{32, 64}
+	fbins       = intList{32, 64}
+	frameLen    = intList{0}
+	hop         = intList{0}
+	spectThresh = intList{40, 50, 60, 70}
+	vBlocks     = intList{4, 8}
+	hBlocks     = intList{4, 8}
+	vBlocks2    = intList{2, 4}
+	hBlocks2    = intList{2, 4}
+	noiseMargin = intList{0, 100, 400}
+	minConf     = floatList{0, 0.02, 0.05, 0.1}
+	maxErr      = intList{0, 1600, 3200}
+)
+
+// row is one grid point and its score
+type row struct {
+	cfg config.Config
+	s   eval.Score
+}
+
+func main() {
+	flag.Var(&tbins, "tbins", "Tbins values")
+	flag.Var(&fbins, "fbins", "Fbins values")
+	flag.Var(&frameLen, "framelen", "FrameLen values; 0 is BufSize/Tbins, not overlapping, else Tbins is (BufSize-FrameLen)/Hop+1, BufSize of -config")
+	flag.Var(&hop, "hop", "Hop values with a FrameLen; 0 is FrameLen")
+	flag.Var(&spectThresh, "spectthresh", "SpectThresh values")
+	flag.Var(&vBlocks, "vblocks", "VBlocks values")
+	flag.Var(&hBlocks, "hblocks", "HBlocks values")
+	flag.Var(&vBlocks2, "vblocks2", "VBlocks2 values")
+	flag.Var(&hBlocks2, "hblocks2", "HBlocks2 values")
+	flag.Var(&noiseMargin, "noisemargin", "NoiseMargin values, margin below is noise; 0 disables")
+	flag.Var(&minConf, "minconf", "MinConfidence values")
+	flag.Var(&maxErr, "maxerr", "MaxErr values; 0 disables")
+	base := flag.String("config", "", "Config file of the params not swept; default config.Default()")
+	gain := flag.Float64("gain", 1.0, "gain applied to wav samples before adc scaling")
+	noCapture := flag.Bool("nocapture", false, "skip adc.CaptureVAD gating and trimming; use the whole recording")
+	top := flag.Int("top", 10, "best grid points listed")
+	out := flag.String("o", "", "write the best Config file here; default stdout")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "usage: dwtune [flags] set\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	for _, st := range spectThresh {
+		if st < 0 || st > 0xFFFF {
+			fmt.Fprintln(os.Stderr, "dwtune:", &config.Error{Field: "SpectThresh", Value: st, Requirement: "a uint16"})
+			os.Exit(2)
+		}
+	}
+	cfg := config.Default()
+	if *base != "" {
+		var err error
+		if cfg, err = config.ReadFile(*base); err != nil {
+			fmt.Fprintln(os.Stderr, "dwtune:", err)
+			os.Exit(2)
+		}
+	}
+	set, err := eval.LoadDir(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dwtune:", err)
+		os.Exit(1)
+	}
+	set.Gain, set.NoGate = *gain, *noCapture
+	set.Capture(cfg) // capture fields are not swept; capture once
+
+	var rows []row
+	invalid, failed := 0, 0
+	for _, c := range grid(cfg) {
+		if err := c.Validate(); err != nil {
+			invalid++
+			continue
+		}
+		r, err := eval.Run(set, c)
+		if err != nil { // a word failed to enroll
+			failed++
+			continue
+		}
+		for _, nm := range noiseMargin {
+			for _, mc := range minConf {
+				for _, me := range maxErr {
+					d := c
+					d.NoiseMargin, d.MinConfidence, d.MaxErr = nm, mc, me
+					r.Decide(d)
+					rows = append(rows, row{d, r.Score()})
+				}
+			}
+		}
+	}
+	fmt.Printf("%s: %d words, %d recordings; %d grid points, %d invalid, %d failed enrollment\n",
+		set.Dir, len(set.Words), len(set.Items), len(rows), invalid, failed)
+	if len(rows) == 0 {
+		fmt.Fprintln(os.Stderr, "dwtune: no grid point ran")
+		os.Exit(1)
+	}
+	sort.SliceStable(rows, func(i, j int) bool { // accuracy, then the fewest false accepts
+		a, b := rows[i].s, rows[j].s
+		if a.Correct != b.Correct {
+			return a.Correct > b.Correct
+		}
+		if a.FalseAcc != b.FalseAcc {
+			return a.FalseAcc < b.FalseAcc
+		}
+		return a.FalseRej < b.FalseRej
+	})
+
+	fmt.Printf("%5s %5s %5s %4s %6s %5s %5s %5s %5s %6s %6s %6s %7s %7s %7s\n", "Tbins", "Fbins", "frame",
+		"hop", "thresh", "vblk", "hblk", "vblk2", "hblk2", "margin", "minconf", "maxerr", "acc", "fa", "fr")
+	for _, rw := range rows[:minInt(*top, len(rows))] {
+		c, s := rw.cfg, rw.s
+		fmt.Printf("%5d %5d %5d %4d %6d %5d %5d %5d %5d %6d %6.3g %6d %7.3f %7.3f %7.3f\n", c.Tbins, c.Fbins,
+			c.FftPoints(), c.HopSize(), c.SpectThresh, c.VBlocks, c.HBlocks, c.VBlocks2, c.HBlocks2, c.NoiseMargin, c.MinConfidence,
+			c.MaxErr, s.Accuracy(), s.FARate(), s.FRRate())
+	}
+
+	best := rows[0]
+	comment := fmt.Sprintf("dwtune %s: accuracy %.3f, false accept %.3f, false reject %.3f over %d trials",
+		set.Dir, best.s.Accuracy(), best.s.FARate(), best.s.FRRate(), best.s.Trials)
+	if *out == "" {
+		err = config.Write(os.Stdout, best.cfg, comment)
+	} else {
+		err = config.WriteFile(*out, best.cfg, comment)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dwtune:", err)
+		os.Exit(1)
+	}
+} // end func main
+
+// grid returns 'c' with each combination of the spectrogram and reduction values; with a
+// FrameLen, Tbins is derived from it and Hop, and the tbins values are not swept
+func grid(c config.Config) (cs []config.Config) {
+	for _, fr := range frames() {
+		for i, tb := range tbins {
+			if fr[0] > 0 && i > 0 {
+				break
+			}
+			for _, fb := range fbins {
+				for _, st := range spectThresh {
+					for _, vb := range vBlocks {
+						for _, hb := range hBlocks {
+							for _, vb2 := range vBlocks2 {
+								for _, hb2 := range hBlocks2 {
+									d := c
+									d.Tbins, d.Fbins, d.SpectThresh = tb, fb, uint16(st)
+									d.FrameLen, d.Hop = fr[0], fr[1]
+									d.DeriveTbins()
+									d.VBlocks, d.HBlocks, d.VBlocks2, d.HBlocks2 = vb, hb, vb2, hb2
+									cs = append(cs, d)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return cs
+} // end func grid
+
+// frames returns each FrameLen, Hop pair of the framelen and hop values; FrameLen 0 once,
+// without a Hop
+func frames() (fs [][2]int) {
+	for _, fl := range frameLen {
+		if fl == 0 {
+			fs = append(fs, [2]int{0, 0})
+			continue
+		}
+		for _, h := range hop {
+			fs = append(fs, [2]int{fl, h})
+		}
+	}
+	return fs
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
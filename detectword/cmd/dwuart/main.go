@@ -0,0 +1,131 @@
+// @file TinyGo/detectword/cmd/dwuart/main.go
+// @date 2026.10.18
+// @info receive the detectword_pico captureDiags uart stream from a serial device, or a
+//       recorded log, into file00_xt.dat, file00_spect.dat, file00_pool1.dat, ...; checks
+//       each array's shape against the params the transmission announces
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+// @date 2026.10.18 -binary reads detectword/frame framed diagnostics, console 'diags binary'
+// @usage: stty -F /dev/ttyACM0 raw; dwuart [-dir out] [-n 1] [-binary] /dev/ttyACM0
+//         dwuart [-dir out] [-n 0] [-renumber] minicom.log     recorded log, or - for stdin
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"localhost/detectword/diag"
+	"localhost/detectword/frame"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory the .dat files are written to")
+	count := flag.Int("n", 1, "transmissions to receive before exit; 0 until the end of the stream")
+	renumber := flag.Bool("renumber", false, "name the files of the k'th transmission fileKK_*.dat, in place of file00_*.dat")
+	echo := flag.Bool("echo", false, "print lines outside transmissions, e.g. --noise-- lines and console replies")
+	force := flag.Bool("force", false, "write files that fail the shape check")
+	binary := flag.Bool("binary", false, "framed binary diagnostics, in place of the tagged text")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "usage: dwuart [flags] /dev/ttyACM0 | file.log | -\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	in := os.Stdin
+	if name := flag.Arg(0); name != "-" {
+		f, err := os.Open(name) // usb cdc ignores baud; set the tty raw with stty first
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "dwuart:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+	var r interface {
+		Next() (*diag.Transmission, error)
+	}
+	var fr *diag.FrameReader
+	if *binary {
+		fr = diag.NewFrameReader(in)
+		if *echo {
+			fr.Other = echoFrame
+		}
+		r = fr
+	} else {
+		tr := diag.NewTextReader(in)
+		if *echo {
+			tr.Other = func(line string) { fmt.Println(line) }
+		}
+		r = tr
+	}
+	exit := 0
+	for k := 0; *count == 0 || k < *count; k++ {
+		t, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "dwuart:", err)
+			os.Exit(1)
+		}
+		p, errs := diag.Check(t)
+		for _, e := range errs {
+			fmt.Fprintf(os.Stderr, "transmission %d: %v\n", k, e)
+		}
+		if len(errs) > 0 {
+			exit = 1
+			if !*force {
+				continue
+			}
+		}
+		fmt.Printf("transmission %d: BufSize %d Tbins %d Fbins %d\n", k, p.BufSize, p.Tbins, p.Fbins)
+		for _, f := range t.Files {
+			name := f.Name
+			if *renumber {
+				name = fmt.Sprintf("file%02d_%s", k, name[strings.IndexByte(name, '_')+1:])
+			}
+			filename := filepath.Join(*dir, filepath.Base(name))
+			if err := os.WriteFile(filename, f.Bytes(), 0644); err != nil {
+				fmt.Fprintln(os.Stderr, "dwuart:", err)
+				os.Exit(1)
+			}
+			fmt.Printf("  %-24s %d lines\n", filename, len(f.Lines))
+		}
+	}
+	if fr != nil && (fr.D.Skipped > 0 || fr.D.BadCrc > 0) {
+		fmt.Fprintf(os.Stderr, "dwuart: %d bytes outside frames, %d frames failed the crc\n", fr.D.Skipped, fr.D.BadCrc)
+	}
+	os.Exit(exit)
+} // end func main
+
+// echoFrame prints a frame outside a transmission, e.g. a detection event
+func echoFrame(f frame.Frame) {
+	switch f.Type {
+	case frame.TypeDetection:
+		d, err := f.Detection()
+		if err != nil {
+			fmt.Println("detection:", err)
+			return
+		}
+		fmt.Printf("detection %s word %d best %d err %d margin %d conf %.3f errs %v\n",
+			d.Outcome, d.Word, d.Best, d.Err, d.Margin, d.Confidence, d.Errs)
+	case frame.TypeNoise:
+		n, err := f.Noise()
+		if err != nil {
+			fmt.Println("noise:", err)
+			return
+		}
+		fmt.Println(diag.NoiseLine(n))
+	default:
+		fmt.Printf("%s frame, %d bytes\n", f.Type, len(f.Payload))
+	}
+}
@@ -0,0 +1,87 @@
+// @file TinyGo/detectword/cmd/dwwav/main.go
+// @date 2026.10.18
+// @info convert adc capture files (Cap2Uart hex, captureDiags decimal) to and from wav,
+//       e.g. to open captures in Audacity, or feed recordings back into the pipeline
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 -r resamples a wav of another rate to the pico's, config.Config.SampleRate()
+
+// @build: go build
+// @usage: dwwav [-base 10] [-sleep 250] file00_xt.dat file00_xt.wav    capture to wav
+//         dwwav -r [-base 10] file00_xt.wav file00_xt.dat              wav to capture
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/dsp"
+	"localhost/detectword/wav"
+)
+
+func main() {
+	base := flag.Int("base", 10, "capture file number base; 16 for Cap2Uart, 10 for captureDiags")
+	def := config.Default()
+	sleep_time := flag.Int("sleep", def.SleepTime, "adc sleep time in us of the capture")
+	get_us := flag.Int("getus", def.GetUs, "adc.Get() time in us")
+	clocked := flag.Bool("clocked", def.Clocked, "adc fifo clocked capture, at the achieved rate of sleep + getus")
+	reverse := flag.Bool("r", false, "convert wav to capture file")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "usage: dwwav [-r] [flags] infile outfile\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	in, out := flag.Arg(0), flag.Arg(1)
+	def.SleepTime, def.GetUs, def.Clocked = *sleep_time, *get_us, *clocked
+	if err := convert(in, out, *base, def.SampleRate(), *reverse); err != nil {
+		fmt.Fprintln(os.Stderr, "dwwav:", err)
+		os.Exit(1)
+	}
+}
+
+// convert converts capture file 'in' to wav 'out', or the reverse; 'picoRate' is the capture
+// sample rate in Hz, written to the wav header, and the rate a wav of another is resampled to
+func convert(in, out string, base int, picoRate float64, reverse bool) error {
+	if !reverse {
+		u, err := adc.ReadSampleFile(in, base)
+		if err != nil {
+			return err
+		}
+		rate := int(picoRate + 0.5) // e.g. (/ 1.0 266e-6) 3759 samp/sec
+		return wav.WriteAdcFile(out, u, rate)
+	}
+	u, rate, err := wav.ReadAdcFile(in)
+	if err != nil {
+		return err
+	}
+	if rate != int(picoRate+0.5) { // e.g. a 44.1 kHz recording, not a dwwav capture
+		fmt.Fprintf(os.Stderr, "dwwav: %s: resampling %d Hz to the pico's %.0f Hz\n", in, rate, picoRate)
+		u = dsp.ResampleUint16(u, float64(rate), picoRate)
+	}
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	format := "%d\n"
+	if base == 16 {
+		format = "%04x\n"
+	}
+	for _, v := range u {
+		fmt.Fprintf(w, format, v)
+	}
+	if err = w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+} // end func convert
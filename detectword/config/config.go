@@ -0,0 +1,308 @@
+// @file TinyGo/detectword/config/config.go
+// @date 2026.10.18
+// @info capture, spectrogram, reduction, detection and enrollment parameters in one Config,
+//       validated once, in place of locals in main() and constants in ReduceWordDetect
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 Validate() VAD and Noise fields, settable from the console
+// @date 2026.10.18 Clocked; hardware clocked capture, SampleRate() of the adc clock divider
+// @date 2026.10.18 FrameLen, Hop; overlapping fft frames, Tbins derived as BufSize/Hop by DeriveTbins()
+// @date 2026.10.18 Spect; complex or real fft spectrogram
+// @date 2026.10.18 Spect 2; Q15 fixed point fft spectrogram, up to 4096 points
+// @date 2026.10.18 Spect 3; mel filterbank energies or MFCCs, MelFilters .. Deltas
+// @date 2026.10.18 NoiseMargin 0; a margin below NoiseMargin is noise, see match.Decide
+// @date 2026.10.18 NoiseMargin 400, the edge of the baseline lse-dse +-400 word band
+// @date 2026.10.18 Default() is --prod--: Takes 1, AdaptNoise false, MinConfidence and MaxErr 0;
+//                  DeltaLseDse fields removed, match.ReduceWordDetect decides by NoiseMargin
+// @date 2026.10.18 Frames(); Tbins derived as (BufSize-FrameLen)/Hop+1, the frames within BufSize,
+//                  not BufSize/Hop with the last frames zero padded
+// @date 2026.10.18 MelFilters 0, MelFilterCount(); 16 filters, fewer for a short fft
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package config
+
+import (
+	"fmt"
+
+	"localhost/adc"
+)
+
+// Config holds every tunable of the detectword pipeline. Default() returns the --prod--
+// values; validate changes with Validate() before use.
+type Config struct {
+	// capture
+	BufSize      int // samples per capture
+	SleepTime    int // us slept per sample; sample time is SleepTime + GetUs, 'Tsamp' in octave mfiles
+	GetUs        int // adc.Get() time, us
+	PreTriggerMs int // ms kept before speech starts
+	MinWordLen   int // samples; shorter captures are not processed
+	VAD          adc.VADParams
+	Noise        adc.NoiseParams
+	AdaptNoise   bool // VAD and noise rejection thresholds follow an adc.NoiseFloor
+	Clocked      bool // samples clocked by the adc free running at SampleRate(), not paced by sleeping
+
+	// spectrogram
+	Tbins       int    // time bins; BufSize/Tbins points per fft, unless FrameLen is set
+	Fbins       int    // frequency bins
+	FrameLen    int    // points per fft, overlapping by FrameLen-Hop; 0 is BufSize/Tbins, not overlapping
+	Hop         int    // points between fft frame starts, Tbins (BufSize-FrameLen)/Hop+1; 0 is FrameLen
+	Spect       int    // dsp.SpectKind; 0 complex fft, both frequency signs, 1 real fft, 0 to fs/2, 2 Q15 fft, 3 mel
+	SpectThresh uint16 // spectrogram values below are set to SpectThresh
+
+	// mel front end, Spect 3, see dsp.MelParams
+	MelFilters int // triangular filters, at most FftPoints()/2+1; 0 is MelFilterCount()'s
+	MelLowHz   int // filterbank range
+	MelHighHz  int // 0 is SampleRate()/2
+	MFCCs      int // cepstral coefficients; 0 is log mel energies
+	Lifter     int // MFCC sinusoidal lifter length; 0 none
+	Deltas     int // MFCC delta orders appended, 0 to 2
+
+	// reduction; blocks per dimension of the avg pool, then of the peak pool of it; V along
+	// the Tbins rows of the spectrogram, H along its Fbins cols
+	VBlocks, HBlocks   int
+	VBlocks2, HBlocks2 int
+
+	// match.Detect decision, see match.DetectParams
+	NoiseMargin   int     // margin below is noise; 0 disables
+	MinConfidence float64 // 0 disables
+	MaxErr        int     // 0 disables
+	Metric        int // match.Metric; 0 square error, 1 dtw
+	DTWBand       int
+
+	// match.EnrollWord, see match.EnrollParams
+	Takes        int
+	RejectFactor float64
+	RejectFloor  int
+	MinAccepted  int
+	KeepTakes    bool
+}
+
+// Default returns the --prod-- Config: one take per word, fixed VAD and noise thresholds,
+// and a word decided only by NoiseMargin, as the pre 2026 firmware; Takes 3 MinAccepted 2,
+// AdaptNoise, MinConfidence and MaxErr are the --dev-- settings to tune with dweval
+func Default() Config {
+	return Config{
+		BufSize: 1024, SleepTime: 250, GetUs: adc.Get_us, PreTriggerMs: adc.PreTriggerMs,
+		MinWordLen: 1024 / 5, VAD: adc.DefaultVADParams(), Noise: adc.DefaultNoiseParams(), AdaptNoise: false,
+		Tbins: 64, Fbins: 64, SpectThresh: 50,
+		MelFilters: 0, MelLowHz: 0, MelHighHz: 0, MFCCs: 0, Lifter: 22, Deltas: 0,
+		VBlocks: 8, HBlocks: 8, VBlocks2: 4, HBlocks2: 4,
+		NoiseMargin: 400, MinConfidence: 0, MaxErr: 0, Metric: 0, DTWBand: 1,
+		Takes: 1, RejectFactor: 1.5, RejectFloor: 200, MinAccepted: 1, KeepTakes: false,
+	}
+}
+
+// Error is a Config field failing validation
+type Error struct {
+	Field       string
+	Value       int
+	Requirement string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("config: %s must be %s, is: %d", e.Field, e.Requirement, e.Value)
+}
+
+// Validate returns an *Error for the first field the pipeline cannot run with; e.g. the
+// fft requires BufSize/Tbins points, a power of 2
+func (c Config) Validate() error {
+	positive := []struct {
+		name string
+		v    int
+	}{
+		{"BufSize", c.BufSize}, {"SleepTime", c.SleepTime}, {"Tbins", c.Tbins}, {"Fbins", c.Fbins},
+		{"VBlocks", c.VBlocks}, {"HBlocks", c.HBlocks}, {"VBlocks2", c.VBlocks2}, {"HBlocks2", c.HBlocks2},
+		{"VAD.FrameLen", c.VAD.FrameLen}, {"Takes", c.Takes}, {"MinAccepted", c.MinAccepted},
+	}
+	for _, f := range positive {
+		if f.v <= 0 {
+			return &Error{f.name, f.v, "positive"}
+		}
+	}
+	for _, f := range []struct {
+		name string
+		v    int
+	}{{"GetUs", c.GetUs}, {"PreTriggerMs", c.PreTriggerMs}, {"MinWordLen", c.MinWordLen}, {"DTWBand", c.DTWBand},
+		{"VAD.StartEnergy", c.VAD.StartEnergy}, {"VAD.StopEnergy", c.VAD.StopEnergy},
+		{"VAD.HangoverFrames", c.VAD.HangoverFrames}, {"VAD.MinSpeechFrames", c.VAD.MinSpeechFrames},
+		{"Noise.Init", c.Noise.Init}, {"Noise.MinStart", c.Noise.MinStart}, {"Noise.MinStop", c.Noise.MinStop},
+		{"Noise.MinPeak", c.Noise.MinPeak}} {
+		if f.v < 0 {
+			return &Error{f.name, f.v, "not negative"}
+		}
+	}
+	if c.VAD.FrameLen >= 512 { // adc.VAD energy accumulator fits 32 bits
+		return &Error{"VAD.FrameLen", c.VAD.FrameLen, "below 512"}
+	}
+	for _, f := range []struct {
+		name string
+		v    int
+	}{{"Noise.RiseShift", c.Noise.RiseShift}, {"Noise.FullShift", c.Noise.FullShift}, {"Noise.FallShift", c.Noise.FallShift}} {
+		if f.v < 0 || f.v > 16 {
+			return &Error{f.name, f.v, "0 to 16"}
+		}
+	}
+	if c.BufSize > 0xFFFF { // store.Params uint16
+		return &Error{"BufSize", c.BufSize, "at most 65535"}
+	}
+	if c.SleepTime > 0xFFFF {
+		return &Error{"SleepTime", c.SleepTime, "at most 65535"}
+	}
+	if c.MinWordLen > c.BufSize {
+		return &Error{"MinWordLen", c.MinWordLen, fmt.Sprintf("at most BufSize %d", c.BufSize)}
+	}
+	if c.FrameLen < 0 || c.Hop < 0 {
+		return &Error{"FrameLen", c.FrameLen, "not negative, as Hop"}
+	}
+	if c.FrameLen == 0 && c.Hop != 0 {
+		return &Error{"Hop", c.Hop, "0 without a FrameLen"}
+	}
+	if c.FrameLen == 0 && (c.BufSize%c.Tbins != 0 || !isPow2(c.FftPoints())) {
+		return &Error{"BufSize/Tbins", c.BufSize / c.Tbins, "a power of 2, the fft size"}
+	}
+	if c.FrameLen > 0 {
+		if !isPow2(c.FrameLen) || c.FrameLen > c.BufSize {
+			return &Error{"FrameLen", c.FrameLen, fmt.Sprintf("a power of 2, the fft size, at most BufSize %d", c.BufSize)}
+		}
+		if hop := c.HopSize(); hop > c.FrameLen || (c.BufSize-c.FrameLen)%hop != 0 {
+			return &Error{"Hop", hop, fmt.Sprintf("at most FrameLen %d, dividing BufSize-FrameLen %d", c.FrameLen, c.BufSize-c.FrameLen)}
+		}
+		if frames := c.Frames(); c.Tbins != frames { // DeriveTbins() sets it
+			return &Error{"Tbins", c.Tbins, fmt.Sprintf("(BufSize-FrameLen)/Hop+1 %d", frames)}
+		}
+		if c.VBlocks > 0 && c.Tbins%c.VBlocks != 0 { // suggest a BufSize giving whole VBlocks
+			n := (c.Tbins + c.VBlocks - 1) / c.VBlocks * c.VBlocks
+			return &Error{"BufSize", c.BufSize, fmt.Sprintf("FrameLen+(Tbins-1)*Hop for Tbins a multiple of VBlocks %d, e.g. %d for %d frames",
+				c.VBlocks, c.FrameLen+(n-1)*c.HopSize(), n)}
+		}
+	}
+	blocks := []struct {
+		name   string
+		v, of  int
+		ofName string
+	}{
+		{"VBlocks", c.VBlocks, c.Tbins, "Tbins"}, {"HBlocks", c.HBlocks, c.Fbins, "Fbins"},
+		{"VBlocks2", c.VBlocks2, c.VBlocks, "VBlocks"}, {"HBlocks2", c.HBlocks2, c.HBlocks, "HBlocks"},
+	}
+	for _, b := range blocks {
+		if !isPow2(b.v) || b.of%b.v != 0 {
+			return &Error{b.name, b.v, "a power of 2 dividing " + b.ofName}
+		}
+	}
+	if c.MinAccepted > c.Takes {
+		return &Error{"MinAccepted", c.MinAccepted, fmt.Sprintf("at most Takes %d", c.Takes)}
+	}
+	if c.Spect < 0 || c.Spect > 3 {
+		return &Error{"Spect", c.Spect, "0 (complex fft), 1 (real fft), 2 (Q15 fft) or 3 (mel)"}
+	}
+	if c.Spect == 2 && c.FftPoints() > 4096 { // dsp.Q15MaxLen
+		return &Error{"FftPoints", c.FftPoints(), "at most 4096 with Spect 2, the Q15 fft"}
+	}
+	if c.Spect == 3 {
+		if err := c.validateMel(); err != nil {
+			return err
+		}
+	}
+	if c.Metric < 0 || c.Metric > 1 {
+		return &Error{"Metric", c.Metric, "0 (square error) or 1 (dtw)"}
+	}
+	return nil
+} // end func Validate
+
+// validateMel checks the mel front end fields, used with Spect 3
+func (c Config) validateMel() error {
+	if bins := c.FftPoints()/2 + 1; c.MelFilters < 0 || c.MelFilters > bins {
+		return &Error{"MelFilters", c.MelFilters, fmt.Sprintf("0 (derived) to FftPoints()/2+1 %d", bins)}
+	}
+	nyquist := int(c.SampleRate() / 2)
+	high := c.MelHighHz
+	if high == 0 {
+		high = nyquist
+	}
+	if c.MelHighHz < 0 || high > nyquist {
+		return &Error{"MelHighHz", c.MelHighHz, fmt.Sprintf("0 or at most SampleRate()/2 %d", nyquist)}
+	}
+	if c.MelLowHz < 0 || c.MelLowHz >= high {
+		return &Error{"MelLowHz", c.MelLowHz, fmt.Sprintf("not negative, below MelHighHz %d", high)}
+	}
+	if n := c.MelFilterCount(); c.MFCCs < 0 || c.MFCCs > n {
+		return &Error{"MFCCs", c.MFCCs, fmt.Sprintf("0 to MelFilterCount() %d", n)}
+	}
+	if c.Lifter < 0 {
+		return &Error{"Lifter", c.Lifter, "not negative"}
+	}
+	if c.Deltas < 0 || c.Deltas > 2 {
+		return &Error{"Deltas", c.Deltas, "0, 1 (deltas) or 2 (and delta-deltas)"}
+	}
+	return nil
+}
+
+// FftPoints returns the points per fft, FrameLen, or BufSize/Tbins when FrameLen is 0
+func (c Config) FftPoints() int {
+	if c.FrameLen > 0 {
+		return c.FrameLen
+	}
+	return c.BufSize / c.Tbins
+}
+
+// HopSize returns the points between fft frame starts; Hop, FrameLen when Hop is 0, or
+// BufSize/Tbins when FrameLen is 0. Frames overlap by FftPoints() - HopSize().
+func (c Config) HopSize() int {
+	switch {
+	case c.FrameLen == 0:
+		return c.BufSize / c.Tbins
+	case c.Hop == 0:
+		return c.FrameLen
+	}
+	return c.Hop
+}
+
+// DefaultMelFilters is the mel filter count MelFilters 0 derives, when the fft has the bins
+const DefaultMelFilters = 16
+
+// MelFilterCount returns the mel filters of Spect 3: MelFilters, or with MelFilters 0
+// DefaultMelFilters, at most one per bin of the FftPoints() real fft, FftPoints()/2+1
+func (c Config) MelFilterCount() int {
+	if c.MelFilters > 0 {
+		return c.MelFilters
+	}
+	if bins := c.FftPoints()/2 + 1; bins < DefaultMelFilters {
+		return bins
+	}
+	return DefaultMelFilters
+}
+
+// Frames returns the FrameLen frames started every Hop within BufSize, (BufSize-FrameLen)/Hop+1;
+// Tbins when FrameLen is 0
+func (c Config) Frames() int {
+	if c.FrameLen == 0 || c.HopSize() <= 0 || c.FrameLen > c.BufSize {
+		return c.Tbins
+	}
+	return (c.BufSize-c.FrameLen)/c.HopSize() + 1
+}
+
+// DeriveTbins sets Tbins to Frames(), the frames of FrameLen and Hop within BufSize, when
+// FrameLen is set; call it after setting fields, before Validate()
+func (c *Config) DeriveTbins() {
+	c.Tbins = c.Frames()
+}
+
+// SampleRate returns the capture sample rate in Hz; the nominal 1e6 / (SleepTime + GetUs) of
+// sleep pacing, or with Clocked the rate of the adc clock divider nearest it, exactly
+func (c Config) SampleRate() float64 {
+	nominal := 1.0e6 / float64(c.SleepTime+c.GetUs)
+	if c.Clocked {
+		_, achieved := adc.ClockDivider(nominal)
+		return achieved
+	}
+	return nominal
+}
+
+// isPow2 is dsp.IsPow2; config does not import dsp
+func isPow2(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
@@ -0,0 +1,146 @@
+// @file TinyGo/detectword/config/fields.go
+// @date 2026.10.18
+// @info Config fields by name for the console get and set commands and Config files; a
+//       table of pointers, as tinygo's reflect is incomplete
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 moved from detectword/console; Config files, file.go, share it
+// @date 2026.10.18 DeltaLseDse, DeltaLseDseNoiseNeg and DeltaLseDseNoisePos removed with the fields
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Field is one settable Config field; exactly one of the pointers is set
+type Field struct {
+	Name string
+	i    *int
+	u    *uint16
+	f    *float64
+	b    *bool
+}
+
+// Fields returns the settable fields of 'c', in Config order
+func (c *Config) Fields() []Field {
+	return []Field{
+		{Name: "BufSize", i: &c.BufSize},
+		{Name: "SleepTime", i: &c.SleepTime},
+		{Name: "GetUs", i: &c.GetUs},
+		{Name: "PreTriggerMs", i: &c.PreTriggerMs},
+		{Name: "MinWordLen", i: &c.MinWordLen},
+		{Name: "VAD.FrameLen", i: &c.VAD.FrameLen},
+		{Name: "VAD.DC", u: &c.VAD.DC},
+		{Name: "VAD.StartEnergy", i: &c.VAD.StartEnergy},
+		{Name: "VAD.StopEnergy", i: &c.VAD.StopEnergy},
+		{Name: "VAD.HangoverFrames", i: &c.VAD.HangoverFrames},
+		{Name: "VAD.MinSpeechFrames", i: &c.VAD.MinSpeechFrames},
+		{Name: "Noise.Init", i: &c.Noise.Init},
+		{Name: "Noise.RiseShift", i: &c.Noise.RiseShift},
+		{Name: "Noise.FullShift", i: &c.Noise.FullShift},
+		{Name: "Noise.FallShift", i: &c.Noise.FallShift},
+		{Name: "Noise.StartRatio", i: &c.Noise.StartRatio},
+		{Name: "Noise.StopRatio", i: &c.Noise.StopRatio},
+		{Name: "Noise.MinStart", i: &c.Noise.MinStart},
+		{Name: "Noise.MinStop", i: &c.Noise.MinStop},
+		{Name: "Noise.PeakRatio", i: &c.Noise.PeakRatio},
+		{Name: "Noise.MinPeak", i: &c.Noise.MinPeak},
+		{Name: "AdaptNoise", b: &c.AdaptNoise},
+		{Name: "Clocked", b: &c.Clocked},
+		{Name: "Tbins", i: &c.Tbins},
+		{Name: "Fbins", i: &c.Fbins},
+		{Name: "FrameLen", i: &c.FrameLen},
+		{Name: "Hop", i: &c.Hop},
+		{Name: "Spect", i: &c.Spect},
+		{Name: "SpectThresh", u: &c.SpectThresh},
+		{Name: "MelFilters", i: &c.MelFilters},
+		{Name: "MelLowHz", i: &c.MelLowHz},
+		{Name: "MelHighHz", i: &c.MelHighHz},
+		{Name: "MFCCs", i: &c.MFCCs},
+		{Name: "Lifter", i: &c.Lifter},
+		{Name: "Deltas", i: &c.Deltas},
+		{Name: "VBlocks", i: &c.VBlocks},
+		{Name: "HBlocks", i: &c.HBlocks},
+		{Name: "VBlocks2", i: &c.VBlocks2},
+		{Name: "HBlocks2", i: &c.HBlocks2},
+		{Name: "NoiseMargin", i: &c.NoiseMargin},
+		{Name: "MinConfidence", f: &c.MinConfidence},
+		{Name: "MaxErr", i: &c.MaxErr},
+		{Name: "Metric", i: &c.Metric},
+		{Name: "DTWBand", i: &c.DTWBand},
+		{Name: "Takes", i: &c.Takes},
+		{Name: "RejectFactor", f: &c.RejectFactor},
+		{Name: "RejectFloor", i: &c.RejectFloor},
+		{Name: "MinAccepted", i: &c.MinAccepted},
+		{Name: "KeepTakes", b: &c.KeepTakes},
+	}
+}
+
+// Lookup returns the field of 'fs' named 'name', ignoring case; nil when none
+func Lookup(fs []Field, name string) *Field {
+	for i := range fs {
+		if strings.EqualFold(fs[i].Name, name) {
+			return &fs[i]
+		}
+	}
+	return nil
+}
+
+func (f Field) String() string {
+	switch {
+	case f.i != nil:
+		return strconv.Itoa(*f.i)
+	case f.u != nil:
+		return strconv.Itoa(int(*f.u))
+	case f.f != nil:
+		return strconv.FormatFloat(*f.f, 'g', -1, 64)
+	case f.b != nil:
+		return strconv.FormatBool(*f.b)
+	}
+	return ""
+}
+
+// Set parses 's' into the field; ints accept 0x hex, bools on and off
+func (f Field) Set(s string) error {
+	switch {
+	case f.i != nil:
+		v, err := strconv.ParseInt(s, 0, 32)
+		if err != nil {
+			return fmt.Errorf("bad int %q", s)
+		}
+		*f.i = int(v)
+	case f.u != nil:
+		v, err := strconv.ParseUint(s, 0, 16)
+		if err != nil {
+			return fmt.Errorf("bad uint16 %q", s)
+		}
+		*f.u = uint16(v)
+	case f.f != nil:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("bad float %q", s)
+		}
+		*f.f = v
+	case f.b != nil:
+		switch strings.ToLower(s) {
+		case "on":
+			s = "true"
+		case "off":
+			s = "false"
+		}
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("bad bool %q", s)
+		}
+		*f.b = v
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// @file TinyGo/detectword/config/file.go
+// @date 2026.10.18
+// @info Config files; one 'Field value' line per field, the pairs of the console set
+//       command, '#' comment lines
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 Read derives Tbins from FrameLen and Hop
+
+// @build: go build
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Write writes every field of 'c', with 'comment' lines first
+func Write(w io.Writer, c Config, comment ...string) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range comment {
+		fmt.Fprintf(bw, "# %s\n", line)
+	}
+	for _, f := range c.Fields() {
+		fmt.Fprintf(bw, "%s %s\n", f.Name, f)
+	}
+	return bw.Flush()
+}
+
+// Read returns Default() with the fields set by the lines of 'r', Tbins derived from
+// FrameLen and Hop when set, validated; blank lines and '#' lines are skipped
+func Read(r io.Reader) (c Config, err error) {
+	c = Default()
+	fs := c.Fields()
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.Fields(line)
+		if len(kv) != 2 {
+			return c, fmt.Errorf("line %d: want field value", lineNum)
+		}
+		f := Lookup(fs, kv[0])
+		if f == nil {
+			return c, fmt.Errorf("line %d: unknown field %q", lineNum, kv[0])
+		}
+		if err := f.Set(kv[1]); err != nil {
+			return c, fmt.Errorf("line %d: %s: %v", lineNum, f.Name, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return c, err
+	}
+	c.DeriveTbins()
+	return c, c.Validate()
+} // end func Read
+
+// ReadFile is Read of 'filename'
+func ReadFile(filename string) (Config, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+	c, err := Read(f)
+	if err != nil {
+		return c, fmt.Errorf("%s: %v", filename, err)
+	}
+	return c, nil
+}
+
+// WriteFile is Write to 'filename'
+func WriteFile(filename string, c Config, comment ...string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err = Write(f, c, comment...); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,413 @@
+// @file TinyGo/detectword/console/console.go
+// @date 2026.10.18
+// @info line based command console for tuning and control over the usb uart; parses and
+//       dispatches commands against a State shared with the firmware main loop
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 diags binary; frame diagnostics
+// @date 2026.10.18 field table moved to config.Fields
+// @date 2026.10.18 state reports the achieved sample rate and adc fifo overruns
+// @date 2026.10.18 set derives Tbins from FrameLen and Hop
+// @date 2026.10.18 enroll says 'once' for the --prod-- single take
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/match"
+	"localhost/detectword/store"
+)
+
+// MaxLine is the longest command line kept; longer lines are discarded with an error
+const MaxLine = 128
+
+// Output is the gpio output mode
+type Output int
+
+const (
+	OutputAuto Output = iota // detections of words 0 and 1 set the output high and low
+	OutputOn                 // forced high
+	OutputOff                // forced low
+)
+
+func (o Output) String() string {
+	switch o {
+	case OutputAuto:
+		return "auto"
+	case OutputOn:
+		return "on"
+	case OutputOff:
+		return "off"
+	}
+	return "unknown"
+}
+
+// NoEnroll is State.Enroll with no re-enrollment requested
+const NoEnroll = -1
+
+// State is the firmware state seen by the console. The firmware updates the report fields
+// before Poll, and acts on the request fields after it; commands never run mid capture.
+type State struct {
+	// requests; set by commands, cleared by the firmware once applied
+	Cfg       config.Config // validated; applied by the firmware when Reconfig
+	Reconfig  bool
+	Enroll    int  // index of a word to re-enroll, NoEnroll when none
+	EnrollAll bool // re-enroll Enroll and every word after it
+	Diags     bool // captureDiags and noiseDiags output
+	Binary    bool // diagnostics as detectword/frame frames, in place of tagged text
+	Output    Output
+
+	// reports; set by the firmware
+	Refs       []match.RefWord // reference words in training order
+	Word       int             // index of the word being enrolled; len(Refs) once trained
+	Only       bool            // only Word is being enrolled; the words after it are kept
+	Takes      int             // accepted takes of Word
+	Light      bool            // output state
+	Noise      *adc.NoiseFloor // nil without an adaptive noise floor
+	Captures   int             // captures processed
+	Rate       float64         // sample rate achieved by the last capture, Hz; 0 unknown
+	Overruns   int             // samples lost to a full adc fifo, Cfg.Clocked, all captures
+	Detections int             // captures detected, of any Outcome
+	Last       match.Detection // latest detection; valid once Detections > 0
+}
+
+// NewState returns a State for 'c' and the reference words 'labels', none enrolled
+func NewState(c config.Config, labels []string) *State {
+	refs := make([]match.RefWord, len(labels))
+	for i, l := range labels {
+		refs[i].Label = l
+	}
+	return &State{Cfg: c, Enroll: NoEnroll, Refs: refs}
+}
+
+// Console executes command lines against a State and writes replies to W
+type Console struct {
+	S   *State
+	W   io.Writer
+	EOL string // reply line ending; "\n\r" on the pico
+
+	line    []byte
+	discard bool // current line is over MaxLine
+}
+
+// New returns a Console on 's' replying to 'w' with "\n" line endings
+func New(s *State, w io.Writer) *Console {
+	return &Console{S: s, W: w, EOL: "\n", line: make([]byte, 0, MaxLine)}
+}
+
+// Run executes each line read from 'r' until EOF; for host use, where reads block
+func (c *Console) Run(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		c.Exec(sc.Text())
+	}
+	return sc.Err()
+}
+
+// Poll executes the complete lines readable from 'r' without blocking, e.g. from
+// machine.Serial, whose Read returns 0 once its buffer is empty; a partial line is kept
+// for the next Poll. Returns the count of lines executed.
+func (c *Console) Poll(r io.Reader) (n int) {
+	var b [16]byte
+	for {
+		k, err := r.Read(b[:])
+		for _, v := range b[:k] {
+			if c.Feed(v) {
+				n++
+			}
+		}
+		if k == 0 || err != nil {
+			return n
+		}
+	}
+}
+
+// Feed adds byte 'b' to the current line, and executes it at '\r' or '\n'; returns true
+// when a line was executed
+func (c *Console) Feed(b byte) bool {
+	if b != '\r' && b != '\n' {
+		if len(c.line) < MaxLine {
+			c.line = append(c.line, b)
+		} else {
+			c.discard = true
+		}
+		return false
+	}
+	line, discard := string(c.line), c.discard
+	c.line, c.discard = c.line[:0], false
+	if discard {
+		c.printf("error: line longer than %d", MaxLine)
+		return false
+	}
+	if strings.TrimSpace(line) == "" {
+		return false
+	}
+	c.Exec(line)
+	return true
+}
+
+// command is a console command; 'run' returns an error reply, or nil after its replies
+type command struct {
+	name  string
+	usage string
+	run   func(c *Console, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"help", "help; list commands", (*Console).help},
+		{"get", "get [field ...]; config values, all without a field", (*Console).get},
+		{"set", "set field value [field value ...]; Tbins derived from FrameLen and Hop; validated together, applied after the current capture", (*Console).set},
+		{"enroll", "enroll word|index|all; repeat the training takes of a word, or of every word", (*Console).enroll},
+		{"list", "list; reference word templates", (*Console).list},
+		{"diags", "diags on|off|binary; capture and noise diagnostics output, as text or frames", (*Console).diags},
+		{"state", "state; training, output, noise floor and last detection", (*Console).state},
+		{"output", "output on|off|auto; force the output, or leave it to detections", (*Console).output},
+	}
+}
+
+// Exec executes one command line
+func (c *Console) Exec(line string) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return
+	}
+	for _, cmd := range commands {
+		if strings.EqualFold(cmd.name, args[0]) {
+			if err := cmd.run(c, args[1:]); err != nil {
+				c.printf("error: %v", err)
+			}
+			return
+		}
+	}
+	c.printf("error: unknown command %q; try help", args[0])
+}
+
+func (c *Console) printf(format string, a ...interface{}) {
+	fmt.Fprintf(c.W, format+c.EOL, a...)
+}
+
+func (c *Console) help(args []string) error {
+	for _, cmd := range commands {
+		c.printf("%s", cmd.usage)
+	}
+	return nil
+}
+
+func (c *Console) get(args []string) error {
+	fs := c.S.Cfg.Fields()
+	if len(args) == 0 {
+		for _, f := range fs {
+			c.printf("%s %s", f.Name, f)
+		}
+		return nil
+	}
+	for _, name := range args { // check all names before replying
+		if config.Lookup(fs, name) == nil {
+			return fmt.Errorf("unknown field %q", name)
+		}
+	}
+	for _, name := range args {
+		f := config.Lookup(fs, name)
+		c.printf("%s %s", f.Name, f)
+	}
+	return nil
+}
+
+func (c *Console) set(args []string) error {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return fmt.Errorf("want field value pairs")
+	}
+	next := c.S.Cfg
+	fs := next.Fields()
+	for i := 0; i < len(args); i += 2 {
+		f := config.Lookup(fs, args[i])
+		if f == nil {
+			return fmt.Errorf("unknown field %q", args[i])
+		}
+		if err := f.Set(args[i+1]); err != nil {
+			return fmt.Errorf("%s: %v", f.Name, err)
+		}
+	}
+	next.DeriveTbins() // e.g. set FrameLen 32 Hop 8
+	if err := next.Validate(); err != nil {
+		return err
+	}
+	retrain := store.ParamsOf(next) != store.ParamsOf(c.S.Cfg)
+	c.S.Cfg = next
+	c.S.Reconfig = true
+	if retrain {
+		c.printf("ok; references were built with other params, every word will be enrolled again")
+		return nil
+	}
+	c.printf("ok")
+	return nil
+}
+
+func (c *Console) enroll(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("want a word, index or all")
+	}
+	if strings.EqualFold(args[0], "all") {
+		c.S.Enroll, c.S.EnrollAll = 0, true
+		c.printf("ok; say each word %s, in order", times(c.S.Cfg.Takes))
+		return nil
+	}
+	k := wordIndex(c.S.Refs, args[0])
+	if k < 0 {
+		return fmt.Errorf("unknown word %q", args[0])
+	}
+	c.S.Enroll, c.S.EnrollAll = k, false
+	c.printf("ok; say %s %s", c.S.Refs[k].Label, times(c.S.Cfg.Takes))
+	return nil
+}
+
+// times returns "once", or "'n' times"
+func times(n int) string {
+	if n == 1 {
+		return "once"
+	}
+	return fmt.Sprintf("%d times", n)
+}
+
+func (c *Console) list(args []string) error {
+	for i, r := range c.S.Refs {
+		status := "enrolled"
+		switch {
+		case i == c.S.Word:
+			status = fmt.Sprintf("enrolling, take %d of %d", c.S.Takes+1, c.S.Cfg.Takes)
+		case i > c.S.Word && !c.S.Only:
+			status = "not enrolled"
+		}
+		rows, cols := 0, 0
+		if len(r.Reduced) > 0 {
+			rows, cols = len(r.Reduced), len(r.Reduced[0])
+		}
+		kept := ""
+		if len(r.Takes) > 0 {
+			kept = fmt.Sprintf(" %d takes", len(r.Takes))
+		}
+		c.printf("%d %s %dx%d%s %s", i, r.Label, rows, cols, kept, status)
+	}
+	return nil
+}
+
+func (c *Console) diags(args []string) error {
+	if len(args) == 1 && strings.EqualFold(args[0], "binary") {
+		c.S.Diags, c.S.Binary = true, true
+		c.printf("ok")
+		return nil
+	}
+	on, err := onOff(args)
+	if err != nil {
+		return err
+	}
+	c.S.Diags, c.S.Binary = on, false
+	c.printf("ok")
+	return nil
+}
+
+func (c *Console) output(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("want on, off or auto")
+	}
+	switch strings.ToLower(args[0]) {
+	case "on":
+		c.S.Output = OutputOn
+	case "off":
+		c.S.Output = OutputOff
+	case "auto":
+		c.S.Output = OutputAuto
+	default:
+		return fmt.Errorf("want on, off or auto, not %q", args[0])
+	}
+	c.printf("ok")
+	return nil
+}
+
+func (c *Console) state(args []string) error {
+	s := c.S
+	if s.Word < len(s.Refs) {
+		c.printf("training %s, word %d of %d, take %d of %d", s.Refs[s.Word].Label, s.Word+1, len(s.Refs),
+			s.Takes+1, s.Cfg.Takes)
+	} else {
+		c.printf("trained %d words", len(s.Refs))
+	}
+	if s.Enroll != NoEnroll {
+		c.printf("enroll pending from word %d", s.Enroll)
+	}
+	if s.Reconfig {
+		c.printf("config change pending")
+	}
+	diags := onOffString(s.Diags)
+	if s.Diags && s.Binary {
+		diags = "binary"
+	}
+	c.printf("output %s light %s diags %s", s.Output, onOffString(s.Light), diags)
+	if s.Noise != nil {
+		start, stop := s.Noise.Thresholds()
+		c.printf("noise floor %d rms %d frames %d start %d stop %d", s.Noise.Energy, s.Noise.Rms(),
+			s.Noise.Frames, start, stop)
+	}
+	c.printf("captures %d detections %d", s.Captures, s.Detections)
+	clock := "paced"
+	if s.Cfg.Clocked {
+		clock = "clocked"
+	}
+	c.printf("sample rate %.1f Hz, configured %.1f Hz %s, overruns %d", s.Rate, s.Cfg.SampleRate(), clock, s.Overruns)
+	if s.Detections > 0 {
+		d := s.Last
+		word := "-"
+		if d.Best >= 0 && d.Best < len(s.Refs) {
+			word = s.Refs[d.Best].Label
+		}
+		c.printf("last %s %s err %d margin %d conf %.3f", d.Outcome, word, d.Err, d.Margin, d.Confidence)
+	}
+	return nil
+}
+
+// wordIndex returns the index in 'refs' of label or decimal index 's'; -1 when none
+func wordIndex(refs []match.RefWord, s string) int {
+	for i, r := range refs {
+		if strings.EqualFold(r.Label, s) {
+			return i
+		}
+	}
+	if k, err := strconv.Atoi(s); err == nil && k >= 0 && k < len(refs) {
+		return k
+	}
+	return -1
+}
+
+func onOff(args []string) (bool, error) {
+	if len(args) == 1 {
+		switch strings.ToLower(args[0]) {
+		case "on":
+			return true, nil
+		case "off":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("want on or off")
+}
+
+func onOffString(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
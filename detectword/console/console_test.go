@@ -0,0 +1,139 @@
+// @file TinyGo/detectword/console/console_test.go
+// @date 2026.10.18
+// @info Exec get, set and validation replies and State requests; Feed and Poll line handling
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package console
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"localhost/detectword/config"
+)
+
+// testConsole returns a Console on a default State with words on, off and dim, and its
+// reply buffer
+func testConsole() (*Console, *bytes.Buffer) {
+	var out bytes.Buffer
+	return New(NewState(config.Default(), []string{"on", "off", "dim"}), &out), &out
+}
+
+func TestExec(t *testing.T) {
+	def := config.Default()
+	tests := []struct {
+		name  string
+		line  string
+		reply string // exact reply, or prefix ending in "..."
+		check func(s *State) bool
+	}{
+		{"get one", "get NoiseMargin", "NoiseMargin 400\n", nil},
+		{"get case", "GET tbins fbins", "Tbins 64\nFbins 64\n", nil},
+		{"get bool", "get AdaptNoise", "AdaptNoise false\n", nil},
+		{"get unknown", "get Tbins Nope", "error: unknown field \"Nope\"\n", nil},
+		{"set", "set NoiseMargin 50", "ok\n",
+			func(s *State) bool { return s.Reconfig && s.Cfg.NoiseMargin == 50 }},
+		{"set hex and bool", "set SpectThresh 0x40 KeepTakes on", "ok; references were built...",
+			func(s *State) bool { return s.Cfg.SpectThresh == 0x40 && s.Cfg.KeepTakes }},
+		{"set float", "set MinConfidence 0.1", "ok\n",
+			func(s *State) bool { return s.Cfg.MinConfidence == 0.1 }},
+		{"set derives Tbins", "set FrameLen 32 Hop 8 BufSize 1048", "ok; references were built...",
+			func(s *State) bool { return s.Cfg.Tbins == (1048-32)/8+1 }},
+		{"set frames past VBlocks", "set FrameLen 32 Hop 16", "error: config: BufSize must be FrameLen+(Tbins-1)*Hop for Tbins a multiple of VBlocks 8, e.g. 1040 for 64 frames, is: 1024\n",
+			func(s *State) bool { return s.Cfg.Tbins == def.Tbins && !s.Reconfig }},
+		{"set retrains", "set VBlocks 4", "ok; references were built with other params, every word will be enrolled again\n",
+			func(s *State) bool { return s.Reconfig && s.Cfg.VBlocks == 4 }},
+		{"set invalid", "set Tbins 48", "error: config: BufSize/Tbins must be...",
+			func(s *State) bool { return !s.Reconfig && s.Cfg.Tbins == def.Tbins }},
+		{"set all or none", "set NoiseMargin 50 HBlocks 3", "error: config: HBlocks...",
+			func(s *State) bool { return !s.Reconfig && s.Cfg.NoiseMargin == def.NoiseMargin }},
+		{"set bad value", "set Tbins many", "error: Tbins: bad int \"many\"\n",
+			func(s *State) bool { return !s.Reconfig }},
+		{"set odd args", "set Tbins", "error: want field value pairs\n", nil},
+		{"set unknown", "set Nope 1", "error: unknown field \"Nope\"\n", nil},
+		{"enroll word", "enroll off", "ok; say off once\n",
+			func(s *State) bool { return s.Enroll == 1 && !s.EnrollAll }},
+		{"enroll index", "enroll 2", "ok; say dim once\n", func(s *State) bool { return s.Enroll == 2 }},
+		{"enroll all", "enroll all", "ok; say each word once, in order\n",
+			func(s *State) bool { return s.Enroll == 0 && s.EnrollAll }},
+		{"enroll unknown", "enroll bright", "error: unknown word \"bright\"\n",
+			func(s *State) bool { return s.Enroll == NoEnroll }},
+		{"diags binary", "diags binary", "ok\n", func(s *State) bool { return s.Diags && s.Binary }},
+		{"output", "output off", "ok\n", func(s *State) bool { return s.Output == OutputOff }},
+		{"output bad", "output dim", "error: want on, off or auto, not \"dim\"\n", nil},
+		{"unknown", "reboot", "error: unknown command \"reboot\"; try help\n", nil},
+		{"blank", "   ", "", nil},
+	}
+	for _, tt := range tests {
+		c, out := testConsole()
+		c.Exec(tt.line)
+		got := out.String()
+		if prefix := strings.TrimSuffix(tt.reply, "..."); prefix != tt.reply {
+			if !strings.HasPrefix(got, prefix) {
+				t.Errorf("%s: %q replied %q, want %q...", tt.name, tt.line, got, prefix)
+			}
+		} else if got != tt.reply {
+			t.Errorf("%s: %q replied %q, want %q", tt.name, tt.line, got, tt.reply)
+		}
+		if tt.check != nil && !tt.check(c.S) {
+			t.Errorf("%s: %q left State %+v", tt.name, tt.line, *c.S)
+		}
+	}
+}
+
+func TestEnrollTakes(t *testing.T) {
+	c, out := testConsole()
+	c.Exec("set Takes 3 MinAccepted 2")
+	out.Reset()
+	c.Exec("enroll 2")
+	if got, want := out.String(), "ok; say dim 3 times\n"; got != want {
+		t.Errorf("enroll with 3 takes replied %q, want %q", got, want)
+	}
+}
+
+func TestSetValidates(t *testing.T) {
+	c, _ := testConsole()
+	c.Exec("set Spect 3") // MelFilters derived for the default 16 point fft
+	if err := c.S.Cfg.Validate(); err != nil || !c.S.Reconfig {
+		t.Fatalf("set Spect 3: Reconfig %v, %v", c.S.Reconfig, err)
+	}
+	c, _ = testConsole()
+	c.Exec("set Spect 3 FrameLen 64 Hop 16 BufSize 1072 MFCCs 13 Deltas 2")
+	if err := c.S.Cfg.Validate(); err != nil || !c.S.Reconfig {
+		t.Fatalf("set mel front end: Reconfig %v, %v", c.S.Reconfig, err)
+	}
+	c.S.Reconfig = false
+	c.Exec("set MelFilters 40") // above FrameLen/2+1
+	if c.S.Reconfig || c.S.Cfg.MelFilters != config.Default().MelFilters {
+		t.Errorf("set MelFilters 40 applied: %d", c.S.Cfg.MelFilters)
+	}
+}
+
+func TestFeedPoll(t *testing.T) {
+	c, out := testConsole()
+	c.EOL = "\n\r"
+	if n := c.Poll(strings.NewReader("get Tbins\r\nget Fb")); n != 1 {
+		t.Errorf("Poll executed %d lines, want 1", n)
+	}
+	if n := c.Poll(strings.NewReader("ins\r")); n != 1 {
+		t.Errorf("Poll of the rest of a line executed %d lines, want 1", n)
+	}
+	if want := "Tbins 64\n\rFbins 64\n\r"; out.String() != want {
+		t.Errorf("replies %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	long := "get " + strings.Repeat("x", MaxLine) + "\n"
+	if n := c.Poll(strings.NewReader(long + "get Hop\n")); n != 1 {
+		t.Errorf("Poll with a long line executed %d lines, want 1", n)
+	}
+	if want := "error: line longer than 128\n\rHop 0\n\r"; out.String() != want {
+		t.Errorf("replies %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,141 @@
+// @file TinyGo/detectword/diag/binary.go
+// @date 2026.10.18
+// @info assembles framed binary diagnostics, detectword/frame, into the transmissions and
+//       files of the text stream, so both are checked and written alike
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 params file of every frame.ParamNames field, FrameLen, Hop, Spect and mel included
+
+// @build: go build
+
+package diag
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"localhost/detectword/frame"
+)
+
+// FrameReader splits a frame stream into transmissions, from a TypeParams frame to a
+// TypeEnd frame. Frames outside a transmission, e.g. TypeDetection events, go to Other
+// when set.
+type FrameReader struct {
+	Other func(f frame.Frame)
+
+	D *frame.Decoder // Skipped and BadCrc count stream errors
+
+	pending *frame.Frame // TypeParams frame read past a transmission lacking its end
+}
+
+// NewFrameReader returns a FrameReader on 'r', a serial device or a recorded stream
+func NewFrameReader(r io.Reader) *FrameReader {
+	return &FrameReader{D: frame.NewDecoder(r)}
+}
+
+// Next returns the next transmission; io.EOF when the stream ends before a TypeParams
+// frame. A transmission cut short by the end of the stream, or by the TypeParams frame of
+// the next, is returned incomplete, with a nil error.
+func (t *FrameReader) Next() (*Transmission, error) {
+	var tr *Transmission
+	var xt *File
+	nextSample := 0
+	for {
+		var f frame.Frame
+		var err error
+		if t.pending != nil {
+			f, t.pending = *t.pending, nil
+		} else {
+			f, err = t.D.Next()
+		}
+		if err == io.EOF {
+			if tr == nil {
+				return nil, io.EOF
+			}
+			return tr, nil
+		}
+		if err != nil {
+			return tr, err
+		}
+		if f.Type == frame.TypeParams && tr != nil { // the previous transmission lost its end
+			t.pending = &f
+			return tr, nil
+		}
+		if tr == nil && f.Type != frame.TypeParams {
+			if t.Other != nil {
+				t.Other(f)
+			}
+			continue
+		}
+		switch f.Type {
+		case frame.TypeParams:
+			c, err := f.Config()
+			tr = &Transmission{}
+			pf := addFile(tr, "params", err == nil)
+			for i, v := range frame.ParamValues(c) {
+				pf.Lines = append(pf.Lines, ParamNames[i]+" "+strconv.Itoa(v))
+			}
+		case frame.TypeSamples:
+			first, samples, err := f.Samples()
+			if xt == nil {
+				xt = addFile(tr, "xt", true)
+			}
+			if err != nil || first != nextSample { // a frame was lost
+				xt.Complete = false
+			}
+			for _, v := range samples {
+				xt.Lines = append(xt.Lines, strconv.Itoa(int(v)))
+			}
+			nextSample = first + len(samples)
+		case frame.TypeSpect:
+			spect, err := f.Spect()
+			sf := addFile(tr, "spect", err == nil)
+			for _, row := range spect {
+				vals := make([]string, len(row))
+				for j, v := range row {
+					vals[j] = strconv.Itoa(int(v))
+				}
+				sf.Lines = append(sf.Lines, strings.Join(vals, " "))
+			}
+		case frame.TypePool1, frame.TypePool2:
+			pool, err := f.Pool()
+			pf := addFile(tr, f.Type.String(), err == nil)
+			for _, row := range pool {
+				vals := make([]string, len(row))
+				for j, v := range row {
+					vals[j] = strconv.Itoa(v)
+				}
+				pf.Lines = append(pf.Lines, strings.Join(vals, " "))
+			}
+		case frame.TypeNoise:
+			n, err := f.Noise()
+			nf := addFile(tr, "noise", err == nil)
+			nf.Lines = append(nf.Lines, NoiseLine(n))
+		case frame.TypeEnd:
+			tr.Complete = true
+			return tr, nil
+		default:
+			if t.Other != nil {
+				t.Other(f)
+			}
+		}
+	}
+} // end func Next
+
+// NoiseLine formats 'n' as the firmware noiseDiags '--noise--' line
+func NoiseLine(n frame.NoiseReport) string {
+	return fmt.Sprintf("--noise-- floor %d rms %d frames %d start %d stop %d peak %d seg %d %d %s clicks %d",
+		n.Energy, n.Rms, n.Frames, n.Start, n.Stop, n.Peak, n.Seg.Start, n.Seg.End, n.Seg.Reason, n.Seg.Clicks)
+}
+
+// addFile adds file00_<kind>.dat to 't'
+func addFile(t *Transmission, kind string, complete bool) *File {
+	f := &File{Name: "file00_" + kind + ".dat", Complete: complete}
+	t.Files = append(t.Files, f)
+	return f
+}
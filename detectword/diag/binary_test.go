@@ -0,0 +1,83 @@
+// @file TinyGo/detectword/diag/binary_test.go
+// @date 2026.10.18
+// @info FrameReader on an encoded transmission of an overlap framed mel config; its params
+//       file parses to the config, as the captureDiags text of the same config does
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package diag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/frame"
+	"localhost/detectword/match"
+	"localhost/detectword/store"
+)
+
+// overlapConfig returns config.Default() with overlapping 64 point frames and mel MFCCs
+func overlapConfig() config.Config {
+	c := config.Default()
+	c.Spect, c.FrameLen, c.Hop, c.BufSize, c.MFCCs, c.Deltas = 3, 64, 16, 1072, 13, 2
+	c.DeriveTbins()
+	return c
+}
+
+func TestFrameReader(t *testing.T) {
+	c := overlapConfig()
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	spect := make([][]uint16, c.Tbins)
+	for i := range spect {
+		spect[i] = make([]uint16, c.Fbins)
+	}
+	pool2, pool1 := match.ReduceWordDetectCreateRef(spect, c.Fbins, c.Tbins, c.VBlocks, c.HBlocks, c.VBlocks2, c.HBlocks2)
+
+	var b bytes.Buffer
+	e := frame.NewEncoder(&b)
+	e.Params(c)
+	e.Samples(make([]uint16, c.BufSize))
+	e.Spect(spect)
+	e.Pool(frame.TypePool1, pool1)
+	e.Pool(frame.TypePool2, pool2)
+	e.Noise(adc.NewNoiseFloor(adc.DefaultNoiseParams()), adc.Segment{}, 0xBFFF)
+	if err := e.End(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewFrameReader(&b)
+	tr, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, errs := Check(tr)
+	if len(errs) != 0 {
+		t.Errorf("Check errors %v", errs)
+	}
+	want := Params{Params: store.ParamsOf(c), Hop: c.Hop, GetUs: c.GetUs}
+	if p != want {
+		t.Errorf("params %+v, want %+v", p, want)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next past the transmission: %v, want io.EOF", err)
+	}
+
+	// the captureDiags text of the same config, one 'name value' line per frame.ParamNames
+	f := &File{Name: "file00_params.dat"}
+	for i, v := range frame.ParamValues(c) {
+		f.Lines = append(f.Lines, fmt.Sprintf("%s %d", frame.ParamNames[i], v))
+	}
+	if got, err := ParseParams(f); err != nil || got != want {
+		t.Errorf("ParseParams of the text = %+v, %v, want %+v", got, err, want)
+	}
+}
@@ -0,0 +1,170 @@
+// @file TinyGo/detectword/diag/shape.go
+// @date 2026.10.18
+// @info the parameters a transmission announces in file00_params.dat, and the array
+//       shapes they imply for the xt, spect, pool1 and pool2 files
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 frame.ParamNames; FrameLen, Hop, Spect and the mel front end optional
+
+// @build: go build
+
+package diag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/frame"
+	"localhost/detectword/match"
+	"localhost/detectword/store"
+)
+
+// Shape is the rows and columns of an array; Rows 0 is any count up to MaxRows
+type Shape struct {
+	Rows, Cols int
+	MaxRows    int
+	U16        bool // values are uint16, e.g. samples
+}
+
+func (s Shape) String() string {
+	if s.Rows == 0 {
+		return fmt.Sprintf("(1..%d)x%d", s.MaxRows, s.Cols)
+	}
+	return fmt.Sprintf("%dx%d", s.Rows, s.Cols)
+}
+
+// ParamNames are the params file keys, in announcement order, frame.ParamNames; the first
+// frame.LegacyParams are required, the rest, FrameLen, Hop, Spect and the mel front end, are
+// config.Default()'s when a capture of earlier firmware lacks them
+var ParamNames = frame.ParamNames
+
+// Params are the announced parameters of a transmission
+type Params struct {
+	store.Params
+	Hop   int // spectrogram frame hop, config.Config's; 0 is FrameLen
+	GetUs int
+}
+
+// ParseParams reads a params file of 'name value' lines
+func ParseParams(f *File) (p Params, err error) {
+	c := config.Default() // the shapes can only be computed for params the pipeline runs with
+	c.MinWordLen = 0
+	vals := frame.ParamValues(c)
+	index := map[string]int{}
+	for i, k := range ParamNames {
+		index[k] = i
+	}
+	seen := make([]bool, len(ParamNames))
+	for i, l := range f.Lines {
+		kv := strings.Fields(l)
+		if len(kv) != 2 {
+			return p, fmt.Errorf("%s line %d: want name value, got %q", f.Name, i+1, l)
+		}
+		v, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return p, fmt.Errorf("%s line %d: bad value %q", f.Name, i+1, kv[1])
+		}
+		if k, ok := index[kv[0]]; ok {
+			vals[k], seen[k] = v, true
+		}
+	}
+	for k, name := range ParamNames[:frame.LegacyParams] {
+		if !seen[k] {
+			return p, fmt.Errorf("%s: missing %s", f.Name, name)
+		}
+	}
+	frame.SetParams(&c, vals)
+	if err := c.Validate(); err != nil {
+		return p, fmt.Errorf("%s: %v", f.Name, err)
+	}
+	return Params{Params: store.ParamsOf(c), Hop: c.Hop, GetUs: c.GetUs}, nil
+}
+
+// Shapes returns the array shape of each file kind for params 'p'; pool shapes are those
+// of a reduction of a zero spectrogram, so they follow match, whatever its block order
+func Shapes(p Params) map[string]Shape {
+	spect := make([][]uint16, p.Tbins)
+	for i := range spect {
+		spect[i] = make([]uint16, p.Fbins)
+	}
+	pool2, pool1 := match.ReduceWordDetectCreateRef(spect, p.Fbins, p.Tbins, p.VBlocks, p.HBlocks, p.VBlocks2, p.HBlocks2)
+	return map[string]Shape{
+		"xt":    {Cols: 1, MaxRows: p.BufSize, U16: true},
+		"spect": {Rows: p.Tbins, Cols: p.Fbins, U16: true},
+		"pool1": shapeOf(pool1),
+		"pool2": shapeOf(pool2),
+	}
+}
+
+func shapeOf(m [][]int) Shape {
+	if len(m) == 0 {
+		return Shape{}
+	}
+	return Shape{Rows: len(m), Cols: len(m[0])}
+}
+
+// Check parses the params file of 't' and validates every file: complete, and of the
+// announced shape. Files of other kinds, e.g. noise, are checked for completeness only.
+func Check(t *Transmission) (p Params, errs []error) {
+	if !t.Complete {
+		errs = append(errs, fmt.Errorf("transmission incomplete; no %s", adc.Tag_eot))
+	}
+	pf := t.File("params")
+	if pf == nil {
+		return p, append(errs, fmt.Errorf("no params file; shapes not checked"))
+	}
+	p, err := ParseParams(pf)
+	if err != nil {
+		return p, append(errs, err)
+	}
+	shapes := Shapes(p)
+	for _, f := range t.Files {
+		if f.Name == "" {
+			errs = append(errs, fmt.Errorf("file without a name"))
+			continue
+		}
+		if !f.Complete {
+			errs = append(errs, fmt.Errorf("%s incomplete; no %s, or a frame lost", f.Name, adc.Tag_eod))
+			continue
+		}
+		want, ok := shapes[f.Kind()]
+		if !ok {
+			continue
+		}
+		m, err := f.Matrix()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := checkShape(f.Name, m, want); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return p, errs
+} // end func Check
+
+// checkShape returns an error when 'm' is not of shape 'want', or holds a value out of
+// its range
+func checkShape(name string, m [][]int, want Shape) error {
+	rows := len(m)
+	if (want.Rows != 0 && rows != want.Rows) || (want.Rows == 0 && (rows < 1 || rows > want.MaxRows)) {
+		return fmt.Errorf("%s: %d rows, want %s", name, rows, want)
+	}
+	for i, r := range m {
+		if len(r) != want.Cols {
+			return fmt.Errorf("%s row %d: %d columns, want %s", name, i+1, len(r), want)
+		}
+		for _, v := range r {
+			if want.U16 && (v < 0 || v > 0xFFFF) {
+				return fmt.Errorf("%s row %d: value %d out of range", name, i+1, v)
+			}
+		}
+	}
+	return nil
+}
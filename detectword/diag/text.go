@@ -0,0 +1,168 @@
+// @file TinyGo/detectword/diag/text.go
+// @date 2026.10.18
+// @info reader for the captureDiags uart stream; '--file--' tagged decimal dumps closed by
+//       '--eod--', a transmission closed by '--eot--'; replaces the raspi uart_xfr tool
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+
+package diag
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"localhost/adc"
+)
+
+// File is one tagged file of a transmission, e.g. file00_spect.dat
+type File struct {
+	Name     string
+	Lines    []string // non-empty lines, trimmed
+	Complete bool     // closed by Tag_eod
+}
+
+// Kind returns the part of Name after the 'fileNN_' prefix and before the extension,
+// e.g. "spect" for file00_spect.dat
+func (f *File) Kind() string {
+	k := f.Name
+	if i := strings.IndexByte(k, '_'); i >= 0 {
+		k = k[i+1:]
+	}
+	if i := strings.LastIndexByte(k, '.'); i >= 0 {
+		k = k[:i]
+	}
+	return k
+}
+
+// Matrix parses Lines as rows of decimal integers
+func (f *File) Matrix() ([][]int, error) {
+	m := make([][]int, len(f.Lines))
+	for i, l := range f.Lines {
+		fields := strings.Fields(l)
+		m[i] = make([]int, len(fields))
+		for j, s := range fields {
+			v, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s line %d: bad value %q", f.Name, i+1, s)
+			}
+			m[i][j] = v
+		}
+	}
+	return m, nil
+}
+
+// Bytes returns Lines as the .dat file, one row per line, values single space separated
+func (f *File) Bytes() []byte {
+	var b bytes.Buffer
+	for _, l := range f.Lines {
+		b.WriteString(strings.Join(strings.Fields(l), " "))
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// Transmission is the files from the first Tag_file to Tag_eot
+type Transmission struct {
+	Files    []*File
+	Complete bool // closed by Tag_eot
+}
+
+// File returns the file of 'kind', e.g. "xt"; nil when none
+func (t *Transmission) File(kind string) *File {
+	for _, f := range t.Files {
+		if f.Kind() == kind {
+			return f
+		}
+	}
+	return nil
+}
+
+// TextReader splits a captureDiags stream into transmissions. Lines outside a file, e.g.
+// console replies and '--noise--' lines, go to Other when set.
+type TextReader struct {
+	Other func(line string)
+
+	sc *bufio.Scanner
+}
+
+// NewTextReader returns a TextReader on 'r', a serial device or a recorded log
+func NewTextReader(r io.Reader) *TextReader {
+	sc := bufio.NewScanner(r)
+	sc.Split(scanLines)
+	return &TextReader{sc: sc}
+}
+
+// Next returns the next transmission; io.EOF when the stream ends before a Tag_file. A
+// transmission cut short by the end of the stream is returned incomplete, with a nil error.
+func (t *TextReader) Next() (*Transmission, error) {
+	var tr *Transmission
+	var cur *File
+	for t.sc.Scan() {
+		line := strings.TrimSpace(t.sc.Text())
+		switch {
+		case strings.Contains(line, adc.Tag_file):
+			if tr == nil {
+				tr = &Transmission{}
+			}
+			cur = &File{Name: fileName(line)}
+			tr.Files = append(tr.Files, cur)
+		case line == adc.Tag_eod:
+			if cur != nil {
+				cur.Complete = true
+			}
+			cur = nil
+		case line == adc.Tag_eot:
+			if tr != nil {
+				tr.Complete = true
+				return tr, nil
+			}
+		case cur != nil:
+			cur.Lines = append(cur.Lines, line)
+		case t.Other != nil:
+			t.Other(line)
+		}
+	}
+	if err := t.sc.Err(); err != nil {
+		return tr, err
+	}
+	if tr == nil {
+		return nil, io.EOF
+	}
+	return tr, nil
+} // end func Next
+
+// fileName returns the name of a Tag_file line, e.g. "........--file-- --file00_xt.dat--"
+func fileName(line string) string {
+	for _, f := range strings.Fields(line) {
+		f = strings.TrimLeft(f, ".")
+		if f == adc.Tag_file || !strings.HasPrefix(f, "--") || !strings.HasSuffix(f, "--") || len(f) <= 4 {
+			continue
+		}
+		return f[2 : len(f)-2]
+	}
+	return ""
+}
+
+// scanLines is bufio.ScanLines ending lines at '\n' or '\r', and skipping empty lines; the
+// pico writes "\n\r", and fmt.Println("\n\r") adds another '\n'
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) && (data[start] == '\n' || data[start] == '\r') {
+		start++
+	}
+	if i := bytes.IndexAny(data[start:], "\r\n"); i >= 0 {
+		return start + i + 1, data[start : start+i], nil
+	}
+	if atEOF && start < len(data) {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
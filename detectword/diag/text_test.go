@@ -0,0 +1,196 @@
+// @file TinyGo/detectword/diag/text_test.go
+// @date 2026.10.18
+// @info TextReader, scanLines, ParseParams and Check on a recorded captureDiags log:
+//       testdata/capture.log, a complete transmission, one with a short spect row, and
+//       one cut short in its spect file
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package diag
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"localhost/detectword/store"
+)
+
+// readLog returns the transmissions of testdata/capture.log, and its lines outside files
+func readLog(t *testing.T) (trs []*Transmission, other []string) {
+	f, err := os.Open("testdata/capture.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r := NewTextReader(f)
+	r.Other = func(line string) { other = append(other, line) }
+	for {
+		tr, err := r.Next()
+		if err == io.EOF {
+			return trs, other
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		trs = append(trs, tr)
+	}
+}
+
+func TestTextReader(t *testing.T) {
+	trs, other := readLog(t)
+	if len(trs) != 3 {
+		t.Fatalf("%d transmissions, want 3", len(trs))
+	}
+	wantOther := []string{"ok", "--noise-- floor 100 rms 10 frames 0 start 1600 stop 400 peak 0 seg 0 0 none clicks 0",
+		"--noise-- between"}
+	if !reflect.DeepEqual(other, wantOther) {
+		t.Errorf("other lines %q, want %q", other, wantOther)
+	}
+	tests := []struct {
+		complete bool
+		names    []string
+		lines    []int // non-empty lines per file
+	}{
+		{true, []string{"file00_params.dat", "file00_xt.dat", "file00_spect.dat", "file00_pool1.dat",
+			"file00_pool2.dat", "file00_noise.dat"}, []int{10, 48, 64, 8, 4, 1}},
+		{true, []string{"file00_params.dat", "file00_xt.dat", "file00_spect.dat", "file00_pool1.dat",
+			"file00_pool2.dat", "file00_noise.dat"}, []int{10, 48, 64, 8, 4, 1}},
+		{false, []string{"file00_params.dat", "file00_xt.dat", "file00_spect.dat"}, []int{10, 48, 10}},
+	}
+	for i, tt := range tests {
+		tr := trs[i]
+		if tr.Complete != tt.complete || len(tr.Files) != len(tt.names) {
+			t.Errorf("transmission %d: complete %v, %d files, want %v, %d", i, tr.Complete, len(tr.Files), tt.complete, len(tt.names))
+			continue
+		}
+		for k, f := range tr.Files {
+			if f.Name != tt.names[k] || len(f.Lines) != tt.lines[k] {
+				t.Errorf("transmission %d file %d: %s of %d lines, want %s of %d", i, k, f.Name, len(f.Lines), tt.names[k], tt.lines[k])
+			}
+			if last := k == len(tr.Files)-1 && !tt.complete; f.Complete == last {
+				t.Errorf("transmission %d: %s Complete %v", i, f.Name, f.Complete)
+			}
+		}
+	}
+	if xt := trs[0].File("xt"); xt == nil || xt.Lines[0] != "32768" || xt.Lines[1] != "33745" {
+		t.Errorf("xt file %v, want samples 32768, 33745, ...", xt)
+	}
+}
+
+func TestScanLines(t *testing.T) {
+	in := "a \n\r\nb\r\nc\n\n\rd\re"
+	sc := bufio.NewScanner(strings.NewReader(in))
+	sc.Split(scanLines)
+	var got []string
+	for sc.Scan() {
+		got = append(got, sc.Text())
+	}
+	if want := []string{"a ", "b", "c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scanLines %q = %q, want %q", in, got, want)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]string{
+		"........--file-- --file00_params.dat--": "file00_params.dat",
+		"--file-- --file00_xt.dat-- ":            "file00_xt.dat",
+		"--file--":                               "",
+		"--file-- ----":                          "",
+	}
+	for line, want := range tests {
+		if got := fileName(line); got != want {
+			t.Errorf("fileName(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	trs, _ := readLog(t)
+	p, err := ParseParams(trs[0].File("params"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Params{Params: store.Params{Tbins: 64, Fbins: 64, BufSize: 1024, SleepTime: 250, VBlocks: 8,
+		HBlocks: 8, VBlocks2: 4, HBlocks2: 4, SpectThresh: 50}, GetUs: 16}
+	if p != want {
+		t.Errorf("ParseParams = %+v, want %+v", p, want)
+	}
+
+	tests := []struct {
+		name  string
+		edit  func(lines []string) []string
+		error string
+	}{
+		{"missing", func(l []string) []string { return l[:len(l)-1] }, "missing SpectThresh"},
+		{"bad value", func(l []string) []string { l[3] = "Tbins many"; return l }, "line 4: bad value"},
+		{"not a pair", func(l []string) []string { l[0] = "BufSize"; return l }, "line 1: want name value"},
+		{"invalid", func(l []string) []string { l[3] = "Tbins 48"; return l }, "BufSize/Tbins"},
+		{"overlap without its Tbins", func(l []string) []string { return append(l, "FrameLen 64", "Hop 16") }, "Tbins must be (BufSize-FrameLen)/Hop+1 61"},
+	}
+	// a capture of a FrameLen firmware; the frames of BufSize 1072 at Hop 16 are 64
+	lines := append([]string(nil), trs[0].File("params").Lines...)
+	lines[0] = "BufSize 1072"
+	lines = append(lines, "FrameLen 64", "Hop 16", "Spect 1")
+	p, err = ParseParams(&File{Name: "file00_params.dat", Lines: lines})
+	want.BufSize, want.FrameLen, want.Spect, want.Hop = 1072, 64, 1, 16
+	if err != nil || p != want {
+		t.Errorf("ParseParams with FrameLen = %+v, %v, want %+v", p, err, want)
+	}
+
+	for _, tt := range tests {
+		f := &File{Name: "file00_params.dat", Lines: tt.edit(append([]string(nil), trs[0].File("params").Lines...))}
+		if _, err := ParseParams(f); err == nil || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("%s: ParseParams error %v, want %q", tt.name, err, tt.error)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	trs, _ := readLog(t)
+	tests := []struct {
+		errs []string
+	}{
+		{nil},
+		{[]string{"file00_spect.dat row 5: 63 columns, want 64x64"}},
+		{[]string{"transmission incomplete", "file00_spect.dat incomplete"}},
+	}
+	for i, tt := range tests {
+		_, errs := Check(trs[i])
+		if len(errs) != len(tt.errs) {
+			t.Errorf("transmission %d: errors %v, want %q", i, errs, tt.errs)
+			continue
+		}
+		for k, err := range errs {
+			if !strings.Contains(err.Error(), tt.errs[k]) {
+				t.Errorf("transmission %d: error %v, want %q", i, err, tt.errs[k])
+			}
+		}
+	}
+
+	// a pool of another shape than the params announce
+	tr := *trs[0]
+	tr.Files = append([]*File(nil), trs[0].Files...)
+	pool2 := *tr.File("pool2")
+	pool2.Lines = pool2.Lines[:3]
+	for k, f := range tr.Files {
+		if f.Kind() == "pool2" {
+			tr.Files[k] = &pool2
+		}
+	}
+	if _, errs := Check(&tr); len(errs) != 1 || !strings.Contains(errs[0].Error(), "3 rows, want 4x4") {
+		t.Errorf("short pool2: errors %v, want 3 rows, want 4x4", errs)
+	}
+
+	noParams := Transmission{Complete: true, Files: trs[0].Files[1:]}
+	if _, errs := Check(&noParams); len(errs) != 1 || !strings.Contains(errs[0].Error(), "no params file") {
+		t.Errorf("no params: errors %v", errs)
+	}
+}
@@ -1,5 +1,5 @@
-// --org-- package gofft
-package main
+// --org-- package gofft; package main until 2026.10.18
+package dsp
 
 import (
 	"fmt"
@@ -5,8 +5,8 @@
 //
 // The algorithm is non-recursive, works in-place overwriting
 // the input array, and requires O(1) additional space.
-// --org-- package gofft
-package main
+// --org-- package gofft; package main until 2026.10.18
+package dsp
 
 import (
 	"math/bits"
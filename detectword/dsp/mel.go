@@ -0,0 +1,214 @@
+// @file TinyGo/detectword/dsp/mel.go
+// @date 2026.10.18
+// @info mel filterbank and MFCC front end; log mel filterbank energies of the RealFFT power
+//       spectrum, optionally their DCT cepstrum, liftered, with delta coefficients, as an
+//       alternative to linear frequency log magnitudes for speaker independent matching
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package dsp
+
+import (
+	"math"
+
+	"localhost/detectword/config"
+)
+
+// MfccZero is the uint16 of a 0 MFCC or delta; coefficients are signed, in dB
+const MfccZero = 0x8000
+
+// DeltaWin is the +-frames of the delta regression
+const DeltaWin = 2
+
+// MelParams are the front end params of config.Config, see MelParamsOf
+type MelParams struct {
+	Filters       int     // triangular filters, equal width on the mel scale
+	SampleRate    float64 // Hz, of the samples as framed
+	LowHz, HighHz float64 // filterbank range; HighHz 0 is SampleRate/2
+	MFCCs         int     // cepstral coefficients kept, c0 first; 0 returns log mel energies
+	Lifter        int     // sinusoidal lifter length; 0 none
+	Deltas        int     // 0 none, 1 deltas, 2 deltas and delta-deltas, appended to the MFCCs
+}
+
+// MelParamsOf returns the MelParams of config 'c'
+func MelParamsOf(c config.Config) MelParams {
+	return MelParams{Filters: c.MelFilterCount(), SampleRate: c.SampleRate(), LowHz: float64(c.MelLowHz),
+		HighHz: float64(c.MelHighHz), MFCCs: c.MFCCs, Lifter: c.Lifter, Deltas: c.Deltas}
+}
+
+// Cols returns the values per frame; Filters, or MFCCs x (1 + Deltas)
+func (p MelParams) Cols() int {
+	if p.MFCCs == 0 {
+		return p.Filters
+	}
+	return p.MFCCs * (1 + p.Deltas)
+}
+
+// HzToMel returns frequency 'hz' on the mel scale, 2595 log10(1 + hz/700)
+func HzToMel(hz float64) float64 {
+	return 2595 * math.Log10(1+hz/700)
+}
+
+// MelToHz is the inverse of HzToMel
+func MelToHz(mel float64) float64 {
+	return 700 * (math.Pow(10, mel/2595) - 1)
+}
+
+// MelFilterbank sums RealFFT power spectrum bins into triangular filters spaced equally on
+// the mel scale, each rising from its lower neighbour's centre to its own and falling to
+// its upper neighbour's
+type MelFilterbank struct {
+	Bins    int // power spectrum bins, frameLen/2+1
+	filters []melFilter
+}
+
+type melFilter struct {
+	first   int       // first bin
+	weights []float64 // of bins first, first+1, ...
+}
+
+// NewMelFilterbank returns the filterbank of p.Filters filters over the frameLen/2+1 bins of
+// a RealFFT of 'frameLen' points. A filter narrower than the bin spacing takes the bin
+// nearest its centre, so no filter is empty.
+func NewMelFilterbank(p MelParams, frameLen int) *MelFilterbank {
+	bins := frameLen/2 + 1
+	high := p.HighHz
+	if high == 0 || high > p.SampleRate/2 {
+		high = p.SampleRate / 2
+	}
+	lo, hi := HzToMel(p.LowHz), HzToMel(high)
+	centres := make([]float64, p.Filters+2) // in bins; edges of the first and last filters included
+	for i := range centres {
+		centres[i] = MelToHz(lo+(hi-lo)*float64(i)/float64(p.Filters+1)) * float64(frameLen) / p.SampleRate
+	}
+	fb := &MelFilterbank{Bins: bins, filters: make([]melFilter, p.Filters)}
+	for m := range fb.filters {
+		left, centre, right := centres[m], centres[m+1], centres[m+2]
+		f := melFilter{first: -1}
+		for k := int(math.Ceil(left)); k <= int(math.Floor(right)) && k < bins; k++ {
+			w := 0.0
+			switch {
+			case float64(k) <= centre && centre > left:
+				w = (float64(k) - left) / (centre - left)
+			case float64(k) > centre && right > centre:
+				w = (right - float64(k)) / (right - centre)
+			}
+			if w <= 0 {
+				continue
+			}
+			if f.first < 0 {
+				f.first = k
+			}
+			for len(f.weights) < k-f.first { // a 0 weight bin between two positive ones
+				f.weights = append(f.weights, 0)
+			}
+			f.weights = append(f.weights, w)
+		}
+		if f.first < 0 { // narrower than a bin
+			f.first = int(math.Floor(centre + 0.5))
+			if f.first >= bins {
+				f.first = bins - 1
+			}
+			f.weights = []float64{1}
+		}
+		fb.filters[m] = f
+	}
+	return fb
+} // end func NewMelFilterbank
+
+// LogEnergies writes the 10 log10 energy, dB, of each filter of power spectrum 'power' to
+// 'out'; an energy below 1 is 0 dB
+func (fb *MelFilterbank) LogEnergies(power []float64, out []float64) {
+	for m, f := range fb.filters {
+		e := 0.0
+		for i, w := range f.weights {
+			e += w * power[f.first+i]
+		}
+		out[m] = 0
+		if e > 1 {
+			out[m] = 10 * math.Log10(e)
+		}
+	}
+}
+
+// MFCC is the cepstrum of log mel energies; an orthonormal DCT-II, coefficients c0 up,
+// with sinusoidal liftering
+type MFCC struct {
+	dct    [][]float64 // [coeff][filter]
+	lifter []float64
+}
+
+// NewMFCC returns the MFCC of 'coeffs' coefficients of 'filters' log mel energies, liftered
+// by 1 + L/2 sin(pi n / L) for 'lifter' L > 0
+func NewMFCC(filters, coeffs, lifter int) *MFCC {
+	c := &MFCC{dct: make([][]float64, coeffs), lifter: make([]float64, coeffs)}
+	for n := range c.dct {
+		scale := math.Sqrt(2 / float64(filters))
+		if n == 0 {
+			scale = math.Sqrt(1 / float64(filters))
+		}
+		c.dct[n] = make([]float64, filters)
+		for m := range c.dct[n] {
+			c.dct[n][m] = scale * math.Cos(math.Pi*float64(n)*(float64(m)+0.5)/float64(filters))
+		}
+		c.lifter[n] = 1
+		if lifter > 0 {
+			c.lifter[n] = 1 + float64(lifter)/2*math.Sin(math.Pi*float64(n)/float64(lifter))
+		}
+	}
+	return c
+}
+
+// Coefficients writes the liftered cepstrum of log mel energies 'logE' to 'out'
+func (c *MFCC) Coefficients(logE []float64, out []float64) {
+	for n, row := range c.dct {
+		v := 0.0
+		for m, d := range row {
+			v += d * logE[m]
+		}
+		out[n] = v * c.lifter[n]
+	}
+}
+
+// Deltas returns the regression deltas along time of 'rows', frames by coefficients, over
+// +-'win' frames; sum t (c[i+t] - c[i-t]) / 2 sum t^2, the edge frames repeated
+func Deltas(rows [][]float64, win int) [][]float64 {
+	d := make([][]float64, len(rows))
+	norm := 0.0
+	for t := 1; t <= win; t++ {
+		norm += 2 * float64(t*t)
+	}
+	last := len(rows) - 1
+	for i := range rows {
+		d[i] = make([]float64, len(rows[i]))
+		for t := 1; t <= win; t++ {
+			next, prev := i+t, i-t
+			if next > last {
+				next = last
+			}
+			if prev < 0 {
+				prev = 0
+			}
+			for j := range d[i] {
+				d[i][j] += float64(t) * (rows[next][j] - rows[prev][j]) / norm
+			}
+		}
+	}
+	return d
+}
+
+// MfccU16 returns MFCC or delta 'v', dB, as a uint16 about MfccZero, saturated
+func MfccU16(v float64) uint16 {
+	u := math.Floor(v+0.5) + MfccZero
+	if u < 0 {
+		return 0
+	}
+	if u > 0xFFFF {
+		return 0xFFFF
+	}
+	return uint16(u)
+}
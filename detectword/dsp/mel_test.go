@@ -0,0 +1,273 @@
+// @file TinyGo/detectword/dsp/mel_test.go
+// @date 2026.10.18
+// @info mel filterbank weights against a float reference, the narrow filter fallback, the
+//       orthonormal DCT and lifter, regression deltas and MfccU16 saturation; the filters
+//       MelParamsOf derives for MelFilters 0
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package dsp
+
+import (
+	"math"
+	"testing"
+
+	"localhost/detectword/config"
+)
+
+// filterWeights returns the weight of each filter of 'fb' on each bin, [filter][bin], read
+// back through LogEnergies of one bin of power at a time
+func filterWeights(fb *MelFilterbank, filters int) [][]float64 {
+	const unit = 1e12
+	w := make([][]float64, filters)
+	for m := range w {
+		w[m] = make([]float64, fb.Bins)
+	}
+	power := make([]float64, fb.Bins)
+	out := make([]float64, filters)
+	for k := range power {
+		power[k] = unit
+		fb.LogEnergies(power, out)
+		for m, db := range out {
+			if db > 0 {
+				w[m][k] = math.Pow(10, db/10) / unit
+			}
+		}
+		power[k] = 0
+	}
+	return w
+}
+
+// refWeights is the textbook triangular filterbank; filter m rises from mel point m to
+// m+1 and falls to m+2, of filters+2 points equally spaced in mel from lowHz to highHz
+func refWeights(filters, frameLen int, rate, lowHz, highHz float64) [][]float64 {
+	mel := func(hz float64) float64 { return 1127 * math.Log(1+hz/700) } // 2595 log10 is 1127 ln
+	hz := func(m float64) float64 { return 700 * (math.Exp(m/1127) - 1) }
+	pts := make([]float64, filters+2)
+	for i := range pts {
+		pts[i] = hz(mel(lowHz)+(mel(highHz)-mel(lowHz))*float64(i)/float64(filters+1)) * float64(frameLen) / rate
+	}
+	w := make([][]float64, filters)
+	for m := range w {
+		w[m] = make([]float64, frameLen/2+1)
+		for k := range w[m] {
+			f := float64(k)
+			w[m][k] = math.Max(0, math.Min((f-pts[m])/(pts[m+1]-pts[m]), (pts[m+2]-f)/(pts[m+2]-pts[m+1])))
+		}
+	}
+	return w
+}
+
+func TestHzToMel(t *testing.T) {
+	for _, tt := range []struct{ hz, mel float64 }{{0, 0}, {700, 2595 * math.Log10(2)}, {1000, 999.9855}} {
+		if m := HzToMel(tt.hz); math.Abs(m-tt.mel) > 1e-4 {
+			t.Errorf("HzToMel(%v) = %v, want %v", tt.hz, m, tt.mel)
+		}
+		if hz := MelToHz(HzToMel(tt.hz)); math.Abs(hz-tt.hz) > 1e-9 {
+			t.Errorf("MelToHz(HzToMel(%v)) = %v", tt.hz, hz)
+		}
+	}
+}
+
+func TestMelFilterbank(t *testing.T) {
+	const rate, frameLen, filters = 8000.0, 256, 12
+	tests := []struct {
+		name          string
+		lowHz, highHz float64
+		refHigh       float64 // the HighHz the filterbank uses
+	}{
+		{"to fs/2", 100, 0, rate / 2},
+		{"band", 300, 3400, 3400},
+		{"above fs/2", 0, 6000, rate / 2},
+	}
+	for _, tt := range tests {
+		fb := NewMelFilterbank(MelParams{Filters: filters, SampleRate: rate, LowHz: tt.lowHz, HighHz: tt.highHz}, frameLen)
+		if fb.Bins != frameLen/2+1 {
+			t.Fatalf("%s: Bins %d, want %d", tt.name, fb.Bins, frameLen/2+1)
+		}
+		got, want := filterWeights(fb, filters), refWeights(filters, frameLen, rate, tt.lowHz, tt.refHigh)
+		for m := range want {
+			for k := range want[m] {
+				if math.Abs(got[m][k]-want[m][k]) > 1e-9 {
+					t.Errorf("%s: filter %d bin %d weight %v, want %v", tt.name, m, k, got[m][k], want[m][k])
+				}
+			}
+		}
+	}
+}
+
+func TestMelFilterbankNarrow(t *testing.T) {
+	// 20 filters over the 9 bins of a 16 point frame; the low filters are narrower than a bin
+	p := MelParams{Filters: 20, SampleRate: 3759, LowHz: 100}
+	fb := NewMelFilterbank(p, 16)
+	w := filterWeights(fb, p.Filters)
+	for m := range w {
+		sum := 0.0
+		for _, v := range w[m] {
+			if v > 1+1e-12 {
+				t.Errorf("filter %d weight %v above 1", m, v)
+			}
+			sum += v
+		}
+		if sum == 0 {
+			t.Errorf("filter %d is empty", m)
+		}
+	}
+}
+
+func TestMelLogEnergies(t *testing.T) {
+	fb := NewMelFilterbank(MelParams{Filters: 4, SampleRate: 8000}, 64)
+	power := make([]float64, fb.Bins)
+	out := make([]float64, 4)
+	fb.LogEnergies(power, out) // silence; energies below 1 are 0 dB
+	for m, v := range out {
+		if v != 0 {
+			t.Errorf("silence filter %d = %v dB, want 0", m, v)
+		}
+	}
+	for k := range power {
+		power[k] = 1e6
+	}
+	fb.LogEnergies(power, out)
+	w := refWeights(4, 64, 8000, 0, 4000)
+	for m := range out {
+		e := 0.0
+		for _, v := range w[m] {
+			e += v * 1e6
+		}
+		if want := 10 * math.Log10(e); math.Abs(out[m]-want) > 1e-9 {
+			t.Errorf("flat filter %d = %v dB, want %v", m, out[m], want)
+		}
+	}
+}
+
+func TestMFCC(t *testing.T) {
+	const n = 16
+	c := NewMFCC(n, n, 0)
+	out := make([]float64, n)
+
+	// orthonormal; a constant is c0 alone, a DCT basis cosine its coefficient alone
+	in := make([]float64, n)
+	for k := 0; k < n; k++ {
+		for m := range in {
+			in[m] = math.Cos(math.Pi * float64(k) * (float64(m) + 0.5) / n)
+		}
+		c.Coefficients(in, out)
+		norm := math.Sqrt(n / 2.0)
+		if k == 0 {
+			norm = math.Sqrt(n)
+		}
+		for j, v := range out {
+			want := 0.0
+			if j == k {
+				want = norm
+			}
+			if math.Abs(v-want) > 1e-9 {
+				t.Errorf("basis %d coefficient %d = %v, want %v", k, j, v, want)
+			}
+		}
+	}
+
+	// energy preserved
+	e, eOut := 0.0, 0.0
+	for m := range in {
+		in[m] = float64(m*m%7) - 3
+		e += in[m] * in[m]
+	}
+	c.Coefficients(in, out)
+	for _, v := range out {
+		eOut += v * v
+	}
+	if math.Abs(e-eOut) > 1e-9 {
+		t.Errorf("energy %v, cepstrum energy %v", e, eOut)
+	}
+
+	// lifter 1 + L/2 sin(pi n/L) and fewer coefficients than filters
+	lc := NewMFCC(n, 13, 22)
+	lout := make([]float64, 13)
+	lc.Coefficients(in, lout)
+	for j := range lout {
+		want := out[j] * (1 + 11*math.Sin(math.Pi*float64(j)/22))
+		if math.Abs(lout[j]-want) > 1e-9 {
+			t.Errorf("liftered coefficient %d = %v, want %v", j, lout[j], want)
+		}
+	}
+}
+
+func TestDeltas(t *testing.T) {
+	rows := make([][]float64, 6)
+	for i := range rows {
+		rows[i] = []float64{2 * float64(i), 5} // ramp of slope 2, and a constant
+	}
+	// (1 (c[i+1] - c[i-1]) + 2 (c[i+2] - c[i-2])) / 10, edge frames repeated
+	want := []float64{1, 1.6, 2, 2, 1.6, 1}
+	d := Deltas(rows, DeltaWin)
+	for i := range d {
+		if math.Abs(d[i][0]-want[i]) > 1e-12 || d[i][1] != 0 {
+			t.Errorf("frame %d deltas %v, want [%v 0]", i, d[i], want[i])
+		}
+	}
+	if d := Deltas(rows[:1], DeltaWin); len(d) != 1 || d[0][0] != 0 {
+		t.Errorf("one frame deltas %v, want 0", d)
+	}
+}
+
+func TestMfccU16(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want uint16
+	}{{0, MfccZero}, {-0.4, MfccZero}, {1.5, MfccZero + 2}, {-12.6, MfccZero - 13}, {-40000, 0}, {40000, 0xFFFF}}
+	for _, tt := range tests {
+		if got := MfccU16(tt.v); got != tt.want {
+			t.Errorf("MfccU16(%v) = %#x, want %#x", tt.v, got, tt.want)
+		}
+	}
+	p := MelParams{Filters: 20}
+	if p.Cols() != 20 {
+		t.Errorf("Cols of log mel = %d, want 20", p.Cols())
+	}
+	p.MFCCs, p.Deltas = 13, 2
+	if p.Cols() != 39 {
+		t.Errorf("Cols of 13 MFCCs and 2 deltas = %d, want 39", p.Cols())
+	}
+}
+
+func TestMelParamsOf(t *testing.T) {
+	tests := []struct {
+		frameLen, hop, bufSize int
+		melFilters             int
+		filters                int
+	}{
+		{0, 0, 1024, 0, 9},    // the 16 point default fft has 9 bins
+		{0, 0, 1024, 5, 5},    // set explicitly
+		{32, 16, 1040, 0, 16}, // 17 bins, DefaultMelFilters
+		{64, 16, 1072, 0, 16},
+		{64, 16, 1072, 33, 33}, // one filter per bin
+	}
+	for _, tt := range tests {
+		c := config.Default()
+		c.Spect, c.FrameLen, c.Hop, c.BufSize, c.MelFilters = 3, tt.frameLen, tt.hop, tt.bufSize, tt.melFilters
+		c.DeriveTbins()
+		if err := c.Validate(); err != nil {
+			t.Errorf("FrameLen %d MelFilters %d: %v", tt.frameLen, tt.melFilters, err)
+			continue
+		}
+		if p := MelParamsOf(c); p.Filters != tt.filters {
+			t.Errorf("FrameLen %d MelFilters %d: %d filters, want %d", tt.frameLen, tt.melFilters, p.Filters, tt.filters)
+		}
+		u := synthWord(c.BufSize, 1)
+		s, bIsNoise := CreateU16SpectConfig(u, Hamming(c.FftPoints()), c, NoiseThreshold)
+		if bIsNoise || len(s) != c.Tbins || len(s[0]) != c.Fbins {
+			t.Errorf("FrameLen %d MelFilters %d: mel spectrogram %d rows, noise %v", tt.frameLen, tt.melFilters, len(s), bIsNoise)
+		}
+	}
+	c := config.Default()
+	c.Spect, c.MelFilters = 3, 10 // above the 9 bins of the default fft
+	if err := c.Validate(); err == nil {
+		t.Error("MelFilters 10 of a 16 point fft validated")
+	}
+}
@@ -0,0 +1,256 @@
+// @file TinyGo/detectword/dsp/q15.go
+// @date 2026.10.18
+// @info Q15 fixed point fft, integer magnitude and lookup table log2/dB, for the RP2040's
+//       Cortex-M0+, which has no FPU; FFT's complex128, cmplx.Sqrt twiddles and the
+//       math.Pow, math.Sqrt and math.Log10 of Magnitude and FftLogShift run in software there
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 q15WindowOf(); Q15Window once per window slice
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package dsp
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// Q15MaxLen is the largest Q15FFT; its twiddles are taken from one quarter wave sine table
+// of Q15MaxLen/4+1 entries, 2KB, strided for smaller transforms
+const Q15MaxLen = 4096
+
+// q15Headroom is the largest magnitude a butterfly stage takes without overflowing int16;
+// a + w*b stays below 8192 + sqrt(2)*8192 = 19777
+const q15Headroom = 1 << 13
+
+var (
+	q15Sin     []int16 // sin(2 pi k / Q15MaxLen) Q15, k <= Q15MaxLen/4
+	q15SinOnce sync.Once
+)
+
+// q15Table builds q15Sin; once, on first use, so firmware not using Q15FFT does not pay for
+// the software float sines at boot
+func q15Table() {
+	q15Sin = make([]int16, Q15MaxLen/4+1)
+	for k := range q15Sin {
+		q15Sin[k] = Q15(math.Sin(2 * math.Pi * float64(k) / Q15MaxLen))
+	}
+}
+
+// Q15 returns 'f', -1 to 1, as Q15, rounded and saturated
+func Q15(f float64) int16 {
+	v := math.Floor(f*32768 + 0.5)
+	if v > 32767 {
+		return 32767
+	}
+	if v < -32768 {
+		return -32768
+	}
+	return int16(v)
+}
+
+// Q15Window returns window 'w', e.g. Hamming(n), as Q15
+func Q15Window(w []float64) []int16 {
+	q := make([]int16, len(w))
+	for i, v := range w {
+		q[i] = Q15(v)
+	}
+	return q
+}
+
+var (
+	q15WinMu sync.Mutex
+	q15WinOf []float64 // the window q15Win was converted from
+	q15Win   []int16
+)
+
+// q15WindowOf returns Q15Window('w'), converted once per window slice; the firmware passes
+// one HammingFftPoints until a console set changes FftPoints()
+func q15WindowOf(w []float64) []int16 {
+	q15WinMu.Lock()
+	defer q15WinMu.Unlock()
+	if len(w) == 0 || len(w) != len(q15WinOf) || &w[0] != &q15WinOf[0] {
+		q15WinOf, q15Win = w, Q15Window(w)
+	}
+	return q15Win
+}
+
+// q15Twiddle returns the cos and sin, Q15, of 2 pi k / n, for k < n/2
+func q15Twiddle(k, n int) (c, s int16) {
+	const quarter = Q15MaxLen / 4
+	i := k * (Q15MaxLen / n)
+	if i <= quarter {
+		return q15Sin[quarter-i], q15Sin[i]
+	}
+	i -= quarter
+	return -q15Sin[i], q15Sin[quarter-i]
+}
+
+// Q15FFT is FFT of Q15 're', 'im', in place, with block floating point scaling: before each
+// butterfly stage able to overflow, every value is halved and 'exp' counted, so the DFT is
+// the output x 2^exp. len(re) must equal len(im), a power of 2, at most Q15MaxLen.
+func Q15FFT(re, im []int16) (exp int, err error) {
+	n := len(re)
+	if err = checkLength("Q15FFT Input", n); err != nil {
+		return 0, err
+	}
+	if n > Q15MaxLen {
+		return 0, &InputSizeError{Context: "Q15FFT Input", Requirement: "at most Q15MaxLen", Size: n}
+	}
+	if len(im) != n {
+		return 0, &InputSizeError{Context: "Q15FFT imaginary Input", Requirement: "len(re)", Size: len(im)}
+	}
+	q15SinOnce.Do(q15Table)
+	permuteQ15(re, im)
+	for size := 2; size <= n; size <<= 1 {
+		for maxAbsQ15(re, im) >= q15Headroom {
+			for i := range re {
+				re[i] >>= 1
+				im[i] >>= 1
+			}
+			exp++
+		}
+		half := size / 2
+		for k := 0; k < half; k++ {
+			wr, ws := q15Twiddle(k, size) // w = exp(-2 pi i k/size) = wr - i ws
+			for a := k; a < n; a += size {
+				b := a + half
+				tr := (int32(wr)*int32(re[b]) + int32(ws)*int32(im[b]) + 1<<14) >> 15
+				ti := (int32(wr)*int32(im[b]) - int32(ws)*int32(re[b]) + 1<<14) >> 15
+				ar, ai := int32(re[a]), int32(im[a])
+				re[a], im[a] = int16(ar+tr), int16(ai+ti)
+				re[b], im[b] = int16(ar-tr), int16(ai-ti)
+			}
+		}
+	}
+	return exp, nil
+} // end func Q15FFT
+
+// permuteQ15 is permute of a Q15 complex vector
+func permuteQ15(re, im []int16) {
+	n := len(re)
+	if n <= 2 {
+		return
+	}
+	shift := 64 - uint64(bits.Len64(uint64(n-1)))
+	for i := 0; i < n; i++ {
+		j := int(bits.Reverse64(uint64(i)) >> shift)
+		if j > i {
+			re[i], re[j] = re[j], re[i]
+			im[i], im[j] = im[j], im[i]
+		}
+	}
+}
+
+func maxAbsQ15(re, im []int16) int32 {
+	m := int32(0)
+	for i := range re {
+		if v := absInt32(int32(re[i])); v > m {
+			m = v
+		}
+		if v := absInt32(int32(im[i])); v > m {
+			m = v
+		}
+	}
+	return m
+}
+
+func absInt32(v int32) int32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// MagnitudeQ15 approximates sqrt(re^2 + im^2) without a multiply; the larger of max and
+// 7/8 max + 1/2 min of |re|, |im|, within -3%/+1%, 0.3 dB
+func MagnitudeQ15(re, im int16) uint32 {
+	a, b := uint32(absInt32(int32(re))), uint32(absInt32(int32(im)))
+	if b > a {
+		a, b = b, a
+	}
+	if m := a - a>>3 + b>>1; m > a {
+		return m
+	}
+	return a
+}
+
+// log2Frac[i] is 256 log2(1 + i/64), the fraction of Log2Q8 by the 6 bits below the top bit
+var log2Frac = [64]uint8{
+	0, 6, 11, 17, 22, 28, 33, 38, 44, 49, 54, 59, 63, 68, 73, 78,
+	82, 87, 92, 96, 100, 105, 109, 113, 118, 122, 126, 130, 134, 138, 142, 146,
+	150, 154, 157, 161, 165, 169, 172, 176, 179, 183, 186, 190, 193, 197, 200, 203,
+	207, 210, 213, 216, 220, 223, 226, 229, 232, 235, 238, 241, 244, 247, 250, 253,
+}
+
+// Log2Q8 returns log2(v) x 256 by log2Frac, truncating to 0.023, 0.14 dB; 0 for v 0 and 1
+func Log2Q8(v uint32) int32 {
+	if v == 0 {
+		return 0
+	}
+	e := bits.Len32(v) - 1
+	var f uint32
+	if e >= 6 {
+		f = v >> uint(e-6) & 63
+	} else {
+		f = v << uint(6-e) & 63
+	}
+	return int32(e)<<8 + int32(log2Frac[f])
+}
+
+// Log2Q8ToDb returns 20 log10 of a value whose Log2Q8 is 'log2q8', in whole dB, truncated as
+// CreateU16SpectFrames' uint16(int(v)); 20 log10(2) x 256 is 1541
+func Log2Q8ToDb(log2q8 int32) int32 {
+	return log2q8 * 1541 >> 16
+}
+
+// NormalizeQ15_ac_threshold is NormalizeU16_ac_threshold in integer arithmetic, halved to
+// fit Q15: the DFT of the returned samples x 2 is that of NormalizeU16_ac_threshold's
+func NormalizeQ15_ac_threshold(data []uint16, dataThreshold uint16) (q []int16, bIsNoise bool) {
+	q = make([]int16, len(data))
+	if len(data) == 0 {
+		return q, true
+	}
+	mi, mx := data[0], data[0]
+	for _, v := range data {
+		if v < mi {
+			mi = v
+		}
+		if v > mx {
+			mx = v
+		}
+	}
+	if mx < dataThreshold || mx > 0xFFF0 { // noise data set, return zeros
+		return q, true
+	}
+	span := int64(mx - mi)
+	if span == 0 {
+		span = 1
+	}
+	scaled := make([]int32, len(data)) // (v-mi)/(mx-mi) x 0xFFFF
+	sum := int64(0)
+	for i, v := range data {
+		scaled[i] = int32(int64(v-mi) * 0xFFFF / span)
+		sum += int64(scaled[i])
+	}
+	avg := int32(sum / int64(len(data)))
+	for i, v := range scaled {
+		q[i] = int16((v - avg) >> 1)
+	}
+	return q, false
+}
+
+// ResizeArrayUint16Int is ResizeArrayUint16 in integer arithmetic
+func ResizeArrayUint16Int(u0 []uint16, n int) []uint16 {
+	n0 := len(u0)
+	u1 := make([]uint16, n)
+	for i := range u1 {
+		u1[i] = u0[i*n0/n]
+	}
+	return u1
+}
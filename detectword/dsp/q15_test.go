@@ -0,0 +1,237 @@
+// @file TinyGo/detectword/dsp/q15_test.go
+// @date 2026.10.18
+// @info Q15 pipeline against the float one on synthetic signals: Q15FFT signal to error
+//       ratio, and CreateU16SpectQ15Frames against CreateU16SpectFrames within 1 dB, its
+//       worst bin, full scale saturation, and the window converted once
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package dsp
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"localhost/detectword/config"
+)
+
+// TestQ15FFT checks Q15FFT x 2^exp against FFT of the same Q15 values, 40 dB signal to error
+// as cmd/dwq15 requires, for noise and sine frames at full scale and -20 dB
+func TestQ15FFT(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 16; n <= Q15MaxLen; n <<= 1 {
+		for _, level := range []float64{0, -20} {
+			amp := math.Pow(10, level/20)
+			for _, sine := range []bool{false, true} {
+				re, im := make([]int16, n), make([]int16, n)
+				c := make([]complex128, n)
+				for i := range re {
+					v := amp * (rnd.Float64()*2 - 1)
+					if sine {
+						v = amp * math.Sin(2*math.Pi*float64(i)*3.3/float64(n))
+					}
+					re[i] = Q15(v)
+					c[i] = complex(float64(re[i]), 0)
+				}
+				exp, err := Q15FFT(re, im)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err = FFT(c); err != nil {
+					t.Fatal(err)
+				}
+				scale := math.Ldexp(1, exp)
+				sig, e := 0.0, 0.0
+				for i := range c {
+					dr, di := real(c[i])-float64(re[i])*scale, imag(c[i])-float64(im[i])*scale
+					sig += real(c[i])*real(c[i]) + imag(c[i])*imag(c[i])
+					e += dr*dr + di*di
+				}
+				if snr := 10 * math.Log10(sig/e); snr < 40 {
+					t.Errorf("n %d level %g dB sine %v: snr %.1f dB, want >= 40", n, level, sine, snr)
+				}
+			}
+		}
+	}
+}
+
+// synthWord returns a capture of 'n' samples about mid scale: a word shaped burst of two
+// gliding tones and their harmonics over a little noise, peak near full scale
+func synthWord(n int, seed int64) []uint16 {
+	rnd := rand.New(rand.NewSource(seed))
+	u := make([]uint16, n)
+	for i := range u {
+		x := float64(i) / float64(n)
+		env := math.Exp(-math.Pow((x-0.5)/0.2, 2)) // burst in the middle
+		f1 := 0.02 + 0.03*x                        // cycles per sample, gliding up
+		f2 := 0.11 - 0.04*x
+		v := env * (0.5*math.Sin(2*math.Pi*f1*float64(i)) + 0.25*math.Sin(4*math.Pi*f1*float64(i)) +
+			0.2*math.Sin(2*math.Pi*f2*float64(i)+1))
+		v += 0.01 * (rnd.Float64()*2 - 1)
+		u[i] = uint16(0x8000 + 0x7FFF*v)
+	}
+	return u
+}
+
+// TestQ15Spect checks the stated tolerance of CreateU16SpectQ15Frames: of the spectrogram
+// bins above SpectThresh, 99% within 1 dB of CreateU16SpectFrames on the same capture, none
+// past 3 dB, and none past 1 dB 20 dB over SpectThresh
+func TestQ15Spect(t *testing.T) {
+	const loud = 20 // dB over SpectThresh
+	// FrameLen, Hop, BufSize of 64 frames; FrameLen 0 is BufSize/Tbins
+	for _, frame := range [][3]int{{0, 0, 1024}, {32, 16, 1040}, {64, 16, 1072}} {
+		frameLen := frame[0]
+		cfg := config.Default()
+		cfg.FrameLen, cfg.Hop, cfg.BufSize = frame[0], frame[1], frame[2]
+		cfg.DeriveTbins()
+		q15 := cfg
+		q15.Spect = int(SpectQ15)
+		if err := q15.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		hamming := Hamming(cfg.FftPoints())
+		bins, in, sum, worst, worstLoud := 0, 0, 0, 0, 0
+		for seed := int64(1); seed <= 4; seed++ {
+			u := synthWord(cfg.BufSize, seed)
+			f, fNoise := CreateU16SpectConfig(u, hamming, cfg, NoiseThreshold)
+			q, qNoise := CreateU16SpectConfig(u, hamming, q15, NoiseThreshold)
+			if fNoise || qNoise {
+				t.Fatalf("FrameLen %d seed %d: noise %v float, %v q15", frameLen, seed, fNoise, qNoise)
+			}
+			for i := range f {
+				for j := range f[i] {
+					if f[i][j] <= cfg.SpectThresh && q[i][j] <= cfg.SpectThresh {
+						continue
+					}
+					d := int(f[i][j]) - int(q[i][j])
+					if d < 0 {
+						d = -d
+					}
+					if d > worst {
+						worst = d
+					}
+					if f[i][j] >= cfg.SpectThresh+loud && d > worstLoud {
+						worstLoud = d
+					}
+					bins++
+					sum += d
+					if d <= 1 {
+						in++
+					}
+				}
+			}
+		}
+		if bins < 100 {
+			t.Fatalf("FrameLen %d: only %d bins above SpectThresh %d", frameLen, bins, cfg.SpectThresh)
+		}
+		frac := float64(in) / float64(bins)
+		t.Logf("FrameLen %d: %d bins, %.4f within 1 dB, mean %.3f dB, worst %d dB, %d dB %d dB over SpectThresh",
+			frameLen, bins, frac, float64(sum)/float64(bins), worst, worstLoud, loud)
+		if frac < 0.99 {
+			t.Errorf("FrameLen %d: %.4f of %d bins within 1 dB, want >= 0.99", frameLen, frac, bins)
+		}
+		// the worst bin: 1 dB once clear of the quiet bins Q15 rounding coarsens
+		if worstLoud > 1 || worst > 3 {
+			t.Errorf("FrameLen %d: worst bin %d dB, %d dB %d dB over SpectThresh; want at most 3, 1", frameLen,
+				worst, worstLoud, loud)
+		}
+	}
+}
+
+// TestQ15Saturation drives Q15, Q15FFT and CreateU16SpectQ15Frames at full scale: values
+// saturate rather than wrap, and the block exponent keeps full scale DC and Nyquist exact
+func TestQ15Saturation(t *testing.T) {
+	for _, tt := range []struct {
+		f    float64
+		want int16
+	}{{1, 32767}, {1.5, 32767}, {-1, -32768}, {-1.5, -32768}, {0.99999, 32767}} {
+		if got := Q15(tt.f); got != tt.want {
+			t.Errorf("Q15(%g) = %d, want %d", tt.f, got, tt.want)
+		}
+	}
+
+	const n = 64
+	for _, tt := range []struct {
+		name string
+		v    func(i int) int16
+		bin  int
+		want float64 // DFT of bin
+	}{
+		{"dc +1", func(i int) int16 { return 32767 }, 0, n * 32767},
+		{"dc -1", func(i int) int16 { return -32768 }, 0, -n * 32768},
+		{"nyquist", func(i int) int16 { return int16(32767 - 65535*(i%2)) }, n / 2, n * 32767.5},
+	} {
+		re, im := make([]int16, n), make([]int16, n)
+		for i := range re {
+			re[i] = tt.v(i)
+		}
+		exp, err := Q15FFT(re, im)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scale := math.Ldexp(1, exp)
+		for k := range re {
+			want := 0.0
+			if k == tt.bin {
+				want = tt.want
+			}
+			if got := float64(re[k]) * scale; math.Abs(got-want) > 0.001*n*32768 || math.Abs(float64(im[k])*scale) > 0.001*n*32768 {
+				t.Errorf("%s: bin %d = %g%+gi, want %g", tt.name, k, got, float64(im[k])*scale, want)
+			}
+		}
+	}
+
+	// a capture swinging the full adc range, 0 to 0xFFF0 below the noise ceiling; its loud
+	// bins within 1 dB of the float spectrogram
+	cfg := config.Default()
+	u := make([]uint16, cfg.BufSize)
+	for i := range u {
+		u[i] = uint16(0x7FF8 + 0x7FF8*math.Sin(2*math.Pi*0.15*float64(i)))
+	}
+	hamming := Hamming(cfg.FftPoints())
+	q15 := cfg
+	q15.Spect = int(SpectQ15)
+	f, fNoise := CreateU16SpectConfig(u, hamming, cfg, NoiseThreshold)
+	q, qNoise := CreateU16SpectConfig(u, hamming, q15, NoiseThreshold)
+	if fNoise || qNoise {
+		t.Fatalf("full scale capture: noise %v float, %v q15", fNoise, qNoise)
+	}
+	peak := 0
+	for i := range f {
+		for j := range f[i] {
+			if f[i][j] > f[0][peak] && i == 0 {
+				peak = j
+			}
+			if d := int(f[i][j]) - int(q[i][j]); (d > 1 || d < -1) && f[i][j] > cfg.SpectThresh+20 {
+				t.Errorf("full scale capture: row %d bin %d %d dB q15, %d dB float", i, j, q[i][j], f[i][j])
+			}
+		}
+	}
+	if f[0][peak] < 100 {
+		t.Errorf("full scale capture: peak %d dB, want a full scale bin", f[0][peak])
+	}
+}
+
+func TestQ15WindowOf(t *testing.T) {
+	h := Hamming(16)
+	q := q15WindowOf(h)
+	if !reflect.DeepEqual(q, Q15Window(h)) {
+		t.Fatalf("q15WindowOf = %v, want Q15Window %v", q, Q15Window(h))
+	}
+	if again := q15WindowOf(h); &again[0] != &q[0] {
+		t.Error("q15WindowOf converted the same window again")
+	}
+	rect := make([]float64, 16) // another window of the same length is converted
+	for i := range rect {
+		rect[i] = 0.5
+	}
+	if got := q15WindowOf(rect); got[0] != Q15(0.5) {
+		t.Errorf("q15WindowOf of a new window = %v, want %d values", got, Q15(0.5))
+	}
+}
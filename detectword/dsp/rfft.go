@@ -0,0 +1,88 @@
+// @file TinyGo/detectword/dsp/rfft.go
+// @date 2026.10.18
+// @info real input fft; N real samples packed into an N/2 point complex FFT, returning only
+//       the N/2+1 non-negative frequency bins, half the transform and buffer of FFT
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package dsp
+
+import "math"
+
+// RealFFT transforms real frames of N samples; the even samples are packed into the real
+// parts, the odd into the imaginary parts, of an N/2 point FFT, and the spectrum of the
+// frame split back out of it. Allocate one per frame size and reuse it.
+type RealFFT struct {
+	n       int
+	z       []complex128 // packed frame, N/2
+	twiddle []complex128 // exp(-2 pi i k/N), k < N/2
+}
+
+// NewRealFFT returns a RealFFT of 'n' samples; 'n' must be a power of 2, at least 2
+func NewRealFFT(n int) (*RealFFT, error) {
+	if err := checkLength("RealFFT Input", n); err != nil {
+		return nil, err
+	}
+	if n < 2 {
+		return nil, &InputSizeError{Context: "RealFFT Input", Requirement: "at least 2", Size: n}
+	}
+	h := n / 2
+	r := &RealFFT{n: n, z: make([]complex128, h), twiddle: make([]complex128, h)}
+	for k := range r.twiddle {
+		a := 2 * math.Pi * float64(k) / float64(n)
+		r.twiddle[k] = complex(math.Cos(a), -math.Sin(a))
+	}
+	return r, nil
+}
+
+// Len returns the frame size N
+func (r *RealFFT) Len() int { return r.n }
+
+// Bins returns the non-negative frequency bins of a transform, N/2+1
+func (r *RealFFT) Bins() int { return r.n/2 + 1 }
+
+// Transform writes the spectrum of real frame 'x', len N, to 'out', len N/2+1, DC first
+// and Nyquist last; bins k and N-k of FFT are conjugates, so these are all of it
+func (r *RealFFT) Transform(x []float64, out []complex128) error {
+	if len(x) != r.n {
+		return &InputSizeError{Context: "RealFFT Input", Requirement: "RealFFT.Len()", Size: len(x)}
+	}
+	if len(out) != r.Bins() {
+		return &InputSizeError{Context: "RealFFT Output", Requirement: "RealFFT.Bins()", Size: len(out)}
+	}
+	h := r.n / 2
+	z := r.z
+	for k := range z {
+		z[k] = complex(x[2*k], x[2*k+1])
+	}
+	fft(z)
+	// Z[k] = E[k] + i O[k] of the even and odd sample DFTs E and O;
+	// E[k] = (Z[k] + conj(Z[h-k]))/2, O[k] = (Z[k] - conj(Z[h-k]))/2i, X[k] = E[k] + W^k O[k]
+	for k := 0; k < h; k++ {
+		zc := conj(z[(h-k)%h])
+		e := (z[k] + zc) * 0.5
+		o := (z[k] - zc) * complex(0, -0.5)
+		out[k] = e + r.twiddle[k]*o
+	}
+	out[h] = complex(real(z[0])-imag(z[0]), 0) // E[0] - O[0], both real
+	return nil
+}
+
+// RFFT returns the N/2+1 non-negative frequency bins of real 'x'; len(x) must be a
+// power of 2, at least 2. Use a RealFFT to transform many frames.
+func RFFT(x []float64) ([]complex128, error) {
+	r, err := NewRealFFT(len(x))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]complex128, r.Bins())
+	return out, r.Transform(x, out)
+}
+
+func conj(c complex128) complex128 {
+	return complex(real(c), -imag(c))
+}
@@ -0,0 +1,137 @@
+// @file TinyGo/detectword/dsp/rfft_test.go
+// @date 2026.10.18
+// @info RealFFT against FFT of the same real frames as complex input; DC and Nyquist
+//       packing, frame reuse and the size errors
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// complexFFT returns the first len(x)/2+1 bins of FFT of real 'x'
+func complexFFT(t *testing.T, x []float64) []complex128 {
+	z := make([]complex128, len(x))
+	for i, v := range x {
+		z[i] = complex(v, 0)
+	}
+	if err := FFT(z); err != nil {
+		t.Fatal(err)
+	}
+	return z[:len(x)/2+1]
+}
+
+func closeBins(a, b []complex128, tol float64) (int, bool) {
+	if len(a) != len(b) {
+		return -1, false
+	}
+	for k := range a {
+		if cmplx.Abs(a[k]-b[k]) > tol {
+			return k, false
+		}
+	}
+	return 0, true
+}
+
+func TestRealFFT(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 2; n <= 1024; n *= 2 {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = rnd.Float64()*65536 - 32768 // i16 sample range
+		}
+		got, err := RFFT(x)
+		if err != nil {
+			t.Fatalf("RFFT %d: %v", n, err)
+		}
+		want := complexFFT(t, x)
+		if k, ok := closeBins(got, want, 1e-9*32768*float64(n)); !ok {
+			t.Errorf("RFFT %d bin %d = %v, want %v", n, k, got[k], want[k])
+		}
+		if imag(got[0]) != 0 || imag(got[n/2]) != 0 {
+			t.Errorf("RFFT %d DC %v, Nyquist %v; want real", n, got[0], got[n/2])
+		}
+	}
+}
+
+func TestRealFFTPacking(t *testing.T) {
+	const n = 16
+	tests := []struct {
+		name string
+		x    func(i int) float64
+		bin  int // the only non-zero bin
+		want complex128
+	}{
+		{"DC", func(i int) float64 { return 3 }, 0, 3 * n},
+		{"Nyquist", func(i int) float64 { return float64(1 - 2*(i%2)) }, n / 2, n},
+		{"cosine bin 2", func(i int) float64 { return math.Cos(2 * math.Pi * 2 * float64(i) / n) }, 2, n / 2},
+		{"sine bin 7", func(i int) float64 { return math.Sin(2 * math.Pi * 7 * float64(i) / n) }, 7, -n / 2 * 1i},
+	}
+	for _, tt := range tests {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = tt.x(i)
+		}
+		got, err := RFFT(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range got {
+			want := complex128(0)
+			if k == tt.bin {
+				want = tt.want
+			}
+			if cmplx.Abs(v-want) > 1e-12 {
+				t.Errorf("%s: bin %d = %v, want %v", tt.name, k, v, want)
+			}
+		}
+	}
+}
+
+func TestRealFFTReuse(t *testing.T) {
+	r, err := NewRealFFT(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 32 || r.Bins() != 17 {
+		t.Fatalf("Len %d, Bins %d; want 32, 17", r.Len(), r.Bins())
+	}
+	out := make([]complex128, r.Bins())
+	for f := 0; f < 3; f++ { // frames through one RealFFT
+		x := make([]float64, 32)
+		for i := range x {
+			x[i] = float64((i*7 + f*13) % 11)
+		}
+		if err := r.Transform(x, out); err != nil {
+			t.Fatal(err)
+		}
+		want := complexFFT(t, x)
+		if k, ok := closeBins(out, want, 1e-9); !ok {
+			t.Errorf("frame %d bin %d = %v, want %v", f, k, out[k], want[k])
+		}
+	}
+}
+
+func TestRealFFTErrors(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 24} {
+		if _, err := NewRealFFT(n); err == nil {
+			t.Errorf("NewRealFFT(%d): no error", n)
+		}
+	}
+	r, _ := NewRealFFT(8)
+	if err := r.Transform(make([]float64, 4), make([]complex128, 5)); err == nil {
+		t.Error("Transform of 4 samples by RealFFT 8: no error")
+	}
+	if err := r.Transform(make([]float64, 8), make([]complex128, 8)); err == nil {
+		t.Error("Transform to 8 bins by RealFFT 8: no error")
+	}
+}
@@ -0,0 +1,602 @@
+// @file TinyGo/detectword/dsp/spect.go
+// @date 2022.03.08
+// @info spectrogram creation; split from detectword_pico/detectword.go
+
+// Copyright 2022 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2022.03.15 added --warning-- CreateU16Spect clipping float %v to uint16 0
+// @date 2022.03.15 copied and updated from sandbox_dw/detectword.go and commented --no pico-- 'exec' use
+// @date 2022.03.27 CreateU16SpectFromU16FromU16(); removed hex processing
+// @date 2022.03.28 FftLogShift(); combines 20*math.Log10() and fft shift
+// @date 2022.04.01 added NormalizeU16_ac_threshold() calls for sound level detection
+// @date 2022.04.08 code cleanup; added bIsNoise as Create*FromU*() return
+// @date 2026.10.18 moved from package main to package dsp; no 'machine' dependency
+// @date 2026.10.18 CreateU16SpectFromU16Noise(); noise threshold parameter, e.g. from adc.NoiseFloor
+// @date 2026.10.18 CreateU16SpectConfig(); params from config.Config; fft size checked by config.Validate(),
+//                  not panic
+// @date 2026.10.18 CreateU16SpectFrames(); overlapping fft frames of 'frameLen' points every 'hop' points,
+//                  CreateU16SpectFromU16Noise() its non-overlapping frameLen == hop == newsize/Tbins case
+// @date 2026.10.18 CreateU16SpectRealFrames(); non-negative frequencies only, by RealFFT; SpectKind selects
+//                  it for CreateU16SpectConfig(); spectRow() thresholds and resizes for both
+// @date 2026.10.18 CreateU16SpectQ15Frames(); integer pipeline by Q15FFT, MagnitudeQ15 and Log2Q8, for the
+//                  FPU-less Cortex-M0+; SpectQ15
+// @date 2026.10.18 CreateU16MelFrames(); log mel filterbank energies or MFCCs with deltas, SpectMel
+// @date 2026.10.18 CreateU16SpectConfig() SpectQ15 window converted once per window, not per capture
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package dsp
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"localhost/detectword/config"
+	// --raspi only-- "os/exec"
+)
+
+// --obs-- deprecated dev code for backards compatability; use for < v0.3 only 
+// CreateSpectFileAndPlot outputs captured spectrogram to file and plots
+// comment with // --no-pico-- for pico jobs
+func CreateSpectFileAndPlot( varname string, Tsamp float64, Tbins, Fbins int, U16Spect [][]uint16 ) {
+	captureFilename := fmt.Sprintf("file000_%s_spect.out",varname)     
+	SpectrogramU16ToFile(captureFilename, Tbins, Fbins, U16Spect)      
+	CreateOctaveSpect(captureFilename, Tsamp)                          
+}
+
+// --obs-- deprecated dev code for backards compatability; use for < v0.3 only 
+func CreateOctaveSpect(captureFilename string, Tsamp float64) {
+	// --raspi only-- osCmd := exec.Command("echo", "") // set osCmd and osErr types
+	// --raspi only-- osErr := osCmd.Run()
+	// --raspi only-- // fmt.Printf("--debug-- creating spectogram plot\n")
+	// --raspi only-- osCmd = exec.Command("octave", "mfiles/plt_spect_nogui2.m", fmt.Sprintf("%f",Tsamp),
+	// --raspi only-- fmt.Sprintf("%s", captureFilename))
+	// --raspi only-- osErr = osCmd.Run()
+	// --raspi only-- if osErr != nil {
+	// --raspi only-- panic(fmt.Sprintf("CreateOcataveSpect exec error: %s",osErr))
+	// --raspi only-- }
+}
+
+// --obs-- keeping this to document the various dev training words from mp3 files
+// CreateGoIncludeVars reads in a hex representation of adc samples, assigns them to a const variable name
+// and creates an include file. Mostly commented since Go const var include files only need updating 
+// when reference word files are recaptured by adc
+func CreateGoIncludeVars() {
+	// create new include vars, e.g light_on, light_off
+	// var sGoIncludeVar string // --dev-- only uncomment when new include files are created
+	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_light01", "light01")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_dark01", "dark01")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])
+
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_rosie01", "rosie01")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_rosie02", "rosie02")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_angel01", "angel01")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_angel02", "angel02")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_light02", "light02")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_light03", "light03")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_light04", "light04")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_dark02", "dark02")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_dark03", "dark03")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])	
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_dark04", "dark04")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])
+
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_mic_light01", "mic_light01")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_mic_light02", "mic_light02")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_mic_dark01", "mic_dark01")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])
+	// sGoIncludeVar = U16HexList2GoIncludeVar("file00_mic_dark02", "mic_dark02")	
+	// fmt.Println("--debug-- sGoIncludeVar:\n\r", sGoIncludeVar[0:64])		
+	
+	// display active varname, e.g. 'light_on', after U16HexList2GoIncludeVar() run;
+	// These must be commented before go include file created and loaded in previous run
+	// fmt.Println("--debug-- light_on: \n\r", light_on[0:64], "\n\r")
+	// fmt.Println("--debug-- light_off: \n\r", light_off[0:64], "\n\r")
+	// fmt.Println("light01: \n\r", light01[0:64], "\n\r")
+	// fmt.Println("dark01: \n\r", dark01[0:64], "\n\r")
+	// fmt.Println("rosie01: \n\r", rosie01[0:64], "\n\r")
+	// fmt.Println("rosie02: \n\r", rosie02[0:64], "\n\r")
+	// fmt.Println("angel01: \n\r", angel01[0:64], "\n\r")
+	// fmt.Println("angel02: \n\r", angel02[0:64], "\n\r")
+	// fmt.Println("light02: \n\r", light02[0:64], "\n\r")
+	// fmt.Println("light03: \n\r", light03[0:64], "\n\r")
+	// fmt.Println("light04: \n\r", light04[0:64], "\n\r")
+	// fmt.Println("dark02: \n\r", dark02[0:64], "\n\r")
+	// fmt.Println("dark03: \n\r", dark03[0:64], "\n\r")
+	// fmt.Println("dark04: \n\r", dark04[0:64], "\n\r")
+	
+} // end func CreateGoIncludeVars() 
+
+// FftLog is 20*math.Log10() of RealFFT magnitudes, DC first; no shift, there are no negative
+// frequencies
+func FftLog(fftReal []float64) (fftLog []float64) {
+	fftLog = make([]float64, len(fftReal))
+	for i,v := range fftReal {
+		fftLog[i] = 20.0*math.Log10(v)
+	}
+	return fftLog
+}
+
+// FftLogShift consolidates fft shift and 20*math.Log10(); specific to CeaateU16SpectFromU16
+func FftLogShift(fftReal []float64) (fftRealShift []float64) {
+	fftRealShift = make([]float64, len(fftReal))
+	mid := len(fftReal)/2
+	for i,v := range fftReal[mid:] {
+		fftRealShift[i] = 20.0*math.Log10(v)
+	}
+	for i:=0; i<mid; i++ {
+		fftRealShift[mid+i]=20*math.Log10(fftReal[i])
+	}
+	return fftRealShift
+} // end func FftLogShift(fftReal []float64) (fftRealShift []float64) {
+
+// CreateU16SpectFromU16 receives 'u16Samples' time domain, and reusable buffer
+// complexFloatArray, resizes tie domain to 'newsize',
+// converts to 16 bit normalized 'ac' []int, loads into real part of []complex128 and
+// create 'Tbins' fft's.  Number of frequency bins are resized to arbitray 'Fbins'.
+// The collection of these fft's is stored as [][]u16Spect.  'newsize' must be a power of 2
+// in place fft calculation.  Final log values below 'threshold' are set to zero on returned 'u16Spect'.
+func CreateU16SpectFromU16 ( u16Samples []uint16, HammingFftPoints []float64, 
+	Tbins, Fbins, newsize int, threshold uint16) (u16Spect [][]uint16, bIsNoise bool) {
+	return CreateU16SpectFromU16Noise( u16Samples, HammingFftPoints, Tbins, Fbins, newsize, threshold, NoiseThreshold )
+}
+
+// SpectKind selects the spectrogram CreateU16SpectConfig creates, config.Config Spect
+type SpectKind int
+
+const (
+	SpectComplex SpectKind = iota // CreateU16SpectFrames; complex FFT, negative then positive frequencies
+	SpectReal                     // CreateU16SpectRealFrames; RealFFT, DC to fs/2
+	SpectQ15                      // CreateU16SpectQ15Frames; Q15FFT, SpectComplex's order, integer only
+	SpectMel                      // CreateU16MelFrames; log mel energies, or MFCCs and deltas
+)
+
+func (k SpectKind) String() string {
+	switch k {
+	case SpectComplex:
+		return "complex"
+	case SpectReal:
+		return "real"
+	case SpectQ15:
+		return "q15"
+	case SpectMel:
+		return "mel"
+	}
+	return "unknown"
+}
+
+// FreqRange returns the lowest and highest frequencies of the Fbins of a SpectKind
+// spectrogram, as fractions of the sample rate; e.g. -0.5, 0.5 for SpectComplex. SpectMel
+// cols are filters or coefficients, not linear in frequency.
+func (k SpectKind) FreqRange() (lo, hi float64) {
+	if k == SpectReal || k == SpectMel {
+		return 0, 0.5
+	}
+	return -0.5, 0.5
+}
+
+// CreateU16SpectConfig is CreateU16SpectFrames, CreateU16SpectRealFrames,
+// CreateU16SpectQ15Frames or CreateU16MelFrames by c.Spect, with the Tbins, Fbins, BufSize,
+// frames and SpectThresh of 'c', which has passed c.Validate(); 'HammingFftPoints' is
+// c.FftPoints() long
+func CreateU16SpectConfig ( u16Samples []uint16, HammingFftPoints []float64, c config.Config,
+	noiseThresh uint16) (u16Spect [][]uint16, bIsNoise bool) {
+	switch SpectKind(c.Spect) {
+	case SpectReal:
+		return CreateU16SpectRealFrames( u16Samples, HammingFftPoints, c.Tbins, c.Fbins, c.BufSize,
+			c.FftPoints(), c.HopSize(), c.SpectThresh, noiseThresh )
+	case SpectQ15: // the window is converted to Q15 once, on its first capture
+		return CreateU16SpectQ15Frames( u16Samples, q15WindowOf(HammingFftPoints), c.Tbins, c.Fbins, c.BufSize,
+			c.FftPoints(), c.HopSize(), c.SpectThresh, noiseThresh )
+	case SpectMel:
+		return CreateU16MelFrames( u16Samples, HammingFftPoints, c.Tbins, c.Fbins, c.BufSize,
+			c.FftPoints(), c.HopSize(), c.SpectThresh, noiseThresh, MelParamsOf(c) )
+	}
+	return CreateU16SpectFrames( u16Samples, HammingFftPoints, c.Tbins, c.Fbins, c.BufSize,
+		c.FftPoints(), c.HopSize(), c.SpectThresh, noiseThresh )
+}
+
+// NoiseThreshold is the fixed CreateU16SpectFromU16 noise filter threshold, 0.75 0xFFFF;
+// a capture peak below is noise
+const NoiseThreshold = 0xBFFF
+
+// CreateU16SpectFromU16Noise is CreateU16SpectFromU16 with noise filter threshold 'noiseThresh',
+// e.g. adc.NoiseFloor.PeakThreshold(), in place of NoiseThreshold
+func CreateU16SpectFromU16Noise ( u16Samples []uint16, HammingFftPoints []float64,
+	Tbins, Fbins, newsize int, threshold, noiseThresh uint16) (u16Spect [][]uint16, bIsNoise bool) {
+	fftPoints := newsize/Tbins // e.g. for 2048: (/ 2048.0 64) 32.0 points per fft (require power of 2)
+	return CreateU16SpectFrames( u16Samples, HammingFftPoints, Tbins, Fbins, newsize, fftPoints, fftPoints,
+		threshold, noiseThresh )
+}
+
+// CreateU16SpectFrames is CreateU16SpectFromU16Noise with 'Tbins' fft frames of 'frameLen'
+// points starting every 'hop' points, in place of newsize/Tbins points each; frames overlap
+// by frameLen-hop points, e.g. 50% for frameLen 32, hop 16, and the Hamming window no longer
+// discards the samples at frame edges. Every frame lies within 'newsize' for config.Validate()'s
+// Tbins, (newsize-frameLen)/hop+1; a frame past it would be zero padded.
+func CreateU16SpectFrames ( u16Samples []uint16, HammingFftPoints []float64,
+	Tbins, Fbins, newsize, frameLen, hop int, threshold, noiseThresh uint16) (u16Spect [][]uint16, bIsNoise bool) {
+	// create 'Tbins' ffts
+	fftPoints := frameLen // require power of 2
+	u16Spect = make([][]uint16, Tbins) // second will be FbinFinal, allocated in main loop
+	if err := checkLength("fft points frameLen", fftPoints); err != nil { // config.Validate() checks first
+		fmt.Fprintf(os.Stderr, "--warning-- %s %v; returned as noise\n\r", GetFunctionName(CreateU16SpectFrames), err)
+		for i,_ := range u16Spect {
+			u16Spect[i] = make([]uint16, Fbins)
+		}
+		return u16Spect, true
+	}
+	complexFloatArray := make( []complex128, fftPoints)
+
+	// noise filter threshold 'noiseThresh', NoiseThreshold 0xBFFF is 0.75 0xFFFF
+	i16Samples, bIsNoise := NormalizeU16_ac_threshold(ResizeArrayUint16(u16Samples, newsize), noiseThresh)
+	if bIsNoise { // finish u16Spect allocation and return zeros
+		for i,_ := range u16Spect {
+			u16Spect[i] = make([]uint16, Fbins)
+		}
+		return u16Spect, bIsNoise // returning zeros indicating noise data set
+	}
+	// --obs-- u16Samples = nil
+	// fmt.Println("--debug-- len normalized resized i16Samples:",len(i16Samples),"\n\r")
+	// fmt.Println("--debug-- i16Samples:", i16Samples[0:8],"\n\r")
+	// fmt.Println("--debug-- i16Samples:", i16Samples,"\n\r")
+
+	lenComplexFloatArray := len(complexFloatArray)
+	lenI16Samples := len(i16Samples)
+	for i:=0; i< Tbins; i++ {
+		start := i*hop // frame start; i*fftPoints without overlap
+		for j:=0; j<lenComplexFloatArray; j++ {
+			if start+j<lenI16Samples {
+				complexFloatArray[j] = complex(
+					HammingFftPoints[j] * float64(i16Samples[start+j]), // Hamming * Sample
+					// float64(i16Samples[i*fftPoints+j]), // Sample
+					0.0)
+			} else {
+				complexFloatArray[j] = complex(0.0,0.0) // zero pad
+			}
+		} // end for j:=0; j<lenComplexFloatArray; j++ 
+
+		err := FFT(complexFloatArray)
+		if err != nil {
+			panic(err)
+		}
+		// if i<2 { fmt.Println("--debug-- CreateU16Spect() FFT():",complexFloatArray[0:4],"\n\r") }
+		fftReal := Magnitude(complexFloatArray)
+		fftRealShift := FftLogShift(fftReal) // --dev-- 20*math.Log10() and fft shift
+		// --obs-- fftReal = nil
+		u16Spect[i] = spectRow(fftRealShift, threshold, Fbins)
+		// fmt.Println("--debug-- u16Spect[i]:", u16Spect[i])
+
+	} // end for i:=0; i< Tbins; i++
+
+	return u16Spect, bIsNoise
+} // end func CreateU16SpectFrames
+
+// CreateU16SpectRealFrames is CreateU16SpectFrames of the non-negative frequencies only; each
+// frame is transformed by a RealFFT, half the complex FFT and its buffer, and its frameLen/2+1
+// bins, DC to fs/2, are resized to 'Fbins'. The negative frequencies CreateU16SpectFrames
+// mirrors carry nothing a real capture does not show in the positive ones.
+func CreateU16SpectRealFrames ( u16Samples []uint16, HammingFftPoints []float64,
+	Tbins, Fbins, newsize, frameLen, hop int, threshold, noiseThresh uint16) (u16Spect [][]uint16, bIsNoise bool) {
+	// create 'Tbins' real ffts
+	u16Spect = make([][]uint16, Tbins) // second will be FbinFinal, allocated in main loop
+	rfft, err := NewRealFFT(frameLen)
+	if err != nil { // config.Validate() checks first
+		fmt.Fprintf(os.Stderr, "--warning-- %s %v; returned as noise\n\r", GetFunctionName(CreateU16SpectRealFrames), err)
+		for i,_ := range u16Spect {
+			u16Spect[i] = make([]uint16, Fbins)
+		}
+		return u16Spect, true
+	}
+
+	// noise filter threshold 'noiseThresh', NoiseThreshold 0xBFFF is 0.75 0xFFFF
+	i16Samples, bIsNoise := NormalizeU16_ac_threshold(ResizeArrayUint16(u16Samples, newsize), noiseThresh)
+	if bIsNoise { // finish u16Spect allocation and return zeros
+		for i,_ := range u16Spect {
+			u16Spect[i] = make([]uint16, Fbins)
+		}
+		return u16Spect, bIsNoise // returning zeros indicating noise data set
+	}
+
+	frame := make([]float64, frameLen) // real frame, and frameLen/2+1 bins; half a complex FFT's
+	bins := make([]complex128, rfft.Bins())
+	lenI16Samples := len(i16Samples)
+	for i:=0; i< Tbins; i++ {
+		start := i*hop
+		for j,_ := range frame {
+			if start+j<lenI16Samples {
+				frame[j] = HammingFftPoints[j] * float64(i16Samples[start+j]) // Hamming * Sample
+			} else {
+				frame[j] = 0.0 // zero pad
+			}
+		}
+		if err := rfft.Transform(frame, bins); err != nil {
+			panic(err)
+		}
+		u16Spect[i] = spectRow(FftLog(Magnitude(bins)), threshold, Fbins) // DC to fs/2
+	} // end for i:=0; i< Tbins; i++
+
+	return u16Spect, bIsNoise
+} // end func CreateU16SpectRealFrames
+
+// CreateU16SpectQ15Frames is CreateU16SpectFrames in integer arithmetic, for the Cortex-M0+
+// with no FPU: samples normalized by NormalizeQ15_ac_threshold, windowed by Q15
+// 'HammingQ15', transformed by Q15FFT, and each bin's dB taken from MagnitudeQ15 and Log2Q8
+// with the block exponent added back. Returns the [][]uint16 of CreateU16SpectFrames, in its
+// FftLogShift order; above 'threshold' 99% of bins are within 1 dB of it, mean 0.12 dB on
+// recordings, see cmd/dwq15. Quiet bins lose resolution to Q15 rounding; 'threshold' hides them.
+func CreateU16SpectQ15Frames ( u16Samples []uint16, HammingQ15 []int16,
+	Tbins, Fbins, newsize, frameLen, hop int, threshold, noiseThresh uint16) (u16Spect [][]uint16, bIsNoise bool) {
+	// create 'Tbins' Q15 ffts
+	u16Spect = make([][]uint16, Tbins) // second will be FbinFinal, allocated in main loop
+	if err := checkLength("fft points frameLen", frameLen); err != nil || frameLen > Q15MaxLen { // config.Validate() checks first
+		fmt.Fprintf(os.Stderr, "--warning-- %s frameLen %d not a power of 2 up to Q15MaxLen; returned as noise\n\r",
+			GetFunctionName(CreateU16SpectQ15Frames), frameLen)
+		for i,_ := range u16Spect {
+			u16Spect[i] = make([]uint16, Fbins)
+		}
+		return u16Spect, true
+	}
+
+	// noise filter threshold 'noiseThresh', NoiseThreshold 0xBFFF is 0.75 0xFFFF
+	q15Samples, bIsNoise := NormalizeQ15_ac_threshold(ResizeArrayUint16Int(u16Samples, newsize), noiseThresh)
+	if bIsNoise { // finish u16Spect allocation and return zeros
+		for i,_ := range u16Spect {
+			u16Spect[i] = make([]uint16, Fbins)
+		}
+		return u16Spect, bIsNoise // returning zeros indicating noise data set
+	}
+
+	re := make([]int16, frameLen)
+	im := make([]int16, frameLen)
+	u16Loader := make([]uint16, frameLen)
+	mid := frameLen/2
+	lenQ15Samples := len(q15Samples)
+	for i:=0; i< Tbins; i++ {
+		start := i*hop
+		for j,_ := range re {
+			re[j], im[j] = 0, 0 // zero pad
+			if start+j<lenQ15Samples {
+				re[j] = int16((int32(HammingQ15[j])*int32(q15Samples[start+j]) + 1<<14) >> 15) // Hamming * Sample
+			}
+		}
+		exp, err := Q15FFT(re, im)
+		if err != nil {
+			panic(err)
+		}
+		// NormalizeQ15_ac_threshold halves the samples, 1 more power of 2
+		scale := int32(exp+1)<<8
+		for k,_ := range re {
+			v := uint16(0)
+			if mag := MagnitudeQ15(re[k], im[k]); mag > 0 {
+				v = uint16(Log2Q8ToDb(Log2Q8(mag) + scale))
+			}
+			if v < threshold {
+				v = threshold
+			}
+			u16Loader[(k+mid)%frameLen] = v // fft shift, as FftLogShift
+		}
+		u16Spect[i] = ResizeArrayUint16Int(u16Loader, Fbins)
+	} // end for i:=0; i< Tbins; i++
+
+	return u16Spect, bIsNoise
+} // end func CreateU16SpectQ15Frames
+
+// CreateU16MelFrames is CreateU16SpectRealFrames through a mel filterbank: each frame's
+// RealFFT power spectrum is summed into p.Filters triangular filters, equally spaced on the
+// mel scale over p.LowHz to p.HighHz, and their dB energies thresholded and resized to 'Fbins'.
+// With p.MFCCs, the liftered DCT cepstrum of the energies is returned instead, followed in
+// each row by p.Deltas orders of deltas along time, as MfccU16 values about MfccZero and not
+// thresholded. Either way the rows are Tbins by Fbins, as ReduceWordDetectCreateRef pools.
+// Frequencies are those of the capture resized to 'newsize', as for every spectrogram.
+func CreateU16MelFrames ( u16Samples []uint16, HammingFftPoints []float64,
+	Tbins, Fbins, newsize, frameLen, hop int, threshold, noiseThresh uint16, p MelParams) (u16Spect [][]uint16, bIsNoise bool) {
+	// create 'Tbins' real ffts
+	u16Spect = make([][]uint16, Tbins) // second will be FbinFinal, allocated in main loop
+	rfft, err := NewRealFFT(frameLen)
+	if err != nil { // config.Validate() checks first
+		fmt.Fprintf(os.Stderr, "--warning-- %s %v; returned as noise\n\r", GetFunctionName(CreateU16MelFrames), err)
+		for i,_ := range u16Spect {
+			u16Spect[i] = make([]uint16, Fbins)
+		}
+		return u16Spect, true
+	}
+
+	// noise filter threshold 'noiseThresh', NoiseThreshold 0xBFFF is 0.75 0xFFFF
+	i16Samples, bIsNoise := NormalizeU16_ac_threshold(ResizeArrayUint16(u16Samples, newsize), noiseThresh)
+	if bIsNoise { // finish u16Spect allocation and return zeros
+		for i,_ := range u16Spect {
+			u16Spect[i] = make([]uint16, Fbins)
+		}
+		return u16Spect, bIsNoise // returning zeros indicating noise data set
+	}
+
+	fb := NewMelFilterbank(p, frameLen)
+	var mfcc *MFCC
+	if p.MFCCs > 0 {
+		mfcc = NewMFCC(p.Filters, p.MFCCs, p.Lifter)
+	}
+	frame := make([]float64, frameLen)
+	bins := make([]complex128, rfft.Bins())
+	power := make([]float64, rfft.Bins())
+	coeffs := make([][]float64, Tbins) // MFCCs per frame, for the deltas
+	lenI16Samples := len(i16Samples)
+	for i:=0; i< Tbins; i++ {
+		start := i*hop
+		for j,_ := range frame {
+			if start+j<lenI16Samples {
+				frame[j] = HammingFftPoints[j] * float64(i16Samples[start+j]) // Hamming * Sample
+			} else {
+				frame[j] = 0.0 // zero pad
+			}
+		}
+		if err := rfft.Transform(frame, bins); err != nil {
+			panic(err)
+		}
+		for k,v := range bins {
+			power[k] = real(v)*real(v) + imag(v)*imag(v)
+		}
+		logE := make([]float64, p.Filters)
+		fb.LogEnergies(power, logE)
+		if mfcc == nil {
+			u16Spect[i] = spectRow(logE, threshold, Fbins)
+			continue
+		}
+		coeffs[i] = make([]float64, p.MFCCs)
+		mfcc.Coefficients(logE, coeffs[i])
+	} // end for i:=0; i< Tbins; i++
+	if mfcc == nil {
+		return u16Spect, bIsNoise
+	}
+
+	// MFCCs, then deltas of each order, per row
+	orders := [][][]float64{coeffs}
+	for d := 0; d < p.Deltas; d++ {
+		orders = append(orders, Deltas(orders[d], DeltaWin))
+	}
+	u16Loader := make([]uint16, p.Cols())
+	for i,_ := range u16Spect {
+		for d,order := range orders {
+			for n,v := range order[i] {
+				u16Loader[d*p.MFCCs+n] = MfccU16(v)
+			}
+		}
+		u16Spect[i] = ResizeArrayUint16(u16Loader, Fbins)
+	}
+	return u16Spect, bIsNoise
+} // end func CreateU16MelFrames
+
+// spectRow clips the log magnitudes 'fftLog' of a frame at 0, raises values below 'threshold'
+// to it, and resizes them to 'Fbins', a row of u16Spect
+func spectRow( fftLog []float64, threshold uint16, Fbins int ) []uint16 {
+	// allocate Fbin dimension of u16Spect, apply threshold, and load return values
+	u16Loader := make([]uint16, len(fftLog))
+	for k,v := range fftLog {
+		if v < 0.0 {
+			fmt.Fprintf(os.Stderr, "--warning-- CreateU16Spect clipped float %v to uint16 0\n\r", v)
+			v = 0.0
+		}
+		if uint16(int(v)) < threshold {
+			u16Loader[k] = threshold
+		} else {
+			u16Loader[k] = uint16(int(v))
+		}
+	}
+	// fmt.Println("--debug-- u16Loader:", u16Loader)
+	return ResizeArrayUint16(u16Loader, Fbins)
+}
+
+// --obs-- deprecated dev code for backards compatability; use for < v0.3 only 
+func CreateU16SpectFromU16_sync ( u16Samples []uint16, complexFloatArray []complex128, HammingFftPoints []float64, 
+	Tbins, Fbins, newsize int, threshold uint16) (u16Spect [][]uint16) {
+	// create 'Tbins' ffts
+	maxCaptureSize := newsize  // e.g. for 8192: (/ 8192.0 128) 64.0 points per fft (require power of 2)
+	fftPoints := maxCaptureSize/Tbins
+	if IsPow2(fftPoints) != true {
+		panic("fft() requires power of 2 input size" + GetFunctionName(CreateU16SpectFromU16))
+	}
+	// --obs-- complexFloatArray := make( []complex128, fftPoints)
+	u16Spect = make([][]uint16, Tbins) // second will be FbinFinal, allocated in main loop
+	// --obs-- u16Samples := StringHexBytes2Uint16( sU16Hex )
+	// --obs-- sU16Hex=""
+	// fmt.Println("--debug-- len u16Samples:",len(u16Samples), "\n\r")
+
+	// noise filter threshold set to 0xBFFF which is 0.75 0xFFFF
+	i16Samples, bIsNoise := NormalizeU16_ac_threshold(ResizeArrayUint16(u16Samples, newsize), 0xBFFF)
+	if bIsNoise { // finish u16Spect allocation and return zeros
+		for i,_ := range u16Spect {
+			u16Spect[i] = make([]uint16, Fbins)
+		}
+		return u16Spect // returning zeros indicating noise data set
+	}
+	u16Samples=nil
+	// fmt.Println("--debug-- len normalized resized i16Samples:",len(i16Samples),"\n\r")
+	// fmt.Println("--debug-- i16Samples:", i16Samples[0:8],"\n\r")
+	// fmt.Println("--debug-- i16Samples:", i16Samples,"\n\r")
+
+	lenComplexFloatArray := len(complexFloatArray)
+	lenI16Samples := len(i16Samples)
+	for i:=0; i< Tbins; i++ {
+		fftReal := HammingFftPoints // fftReal multi-tasks between fft result and Hamming multiplier
+		for j:=0; j<lenComplexFloatArray; j++ {
+			if i*fftPoints+j<lenI16Samples {
+				complexFloatArray[j] = complex(
+					fftReal[j] * float64(i16Samples[i*fftPoints+j]), // Hamming * Sample
+					// float64(i16Samples[i*fftPoints+j]), // Sample
+					0.0)
+			} else {
+				complexFloatArray[j] = complex(0.0,0.0) // zero pad
+			}
+		} // end for j:=0; j<lenComplexFloatArray; j++ 
+
+		// if i<2 { fmt.Println("--debug-- #####\n\r") }
+		// if i<2 { fmt.Println("--debug-- CreateU16Spect() complexFloatArray[0:4]:", complexFloatArray[0:4],"\n\r") }
+		err := FFT(complexFloatArray)
+		if err != nil {
+			panic(err)
+		}
+		// if i<2 { fmt.Println("--debug-- CreateU16Spect() FFT():",complexFloatArray[0:4],"\n\r") }
+		fftReal = Magnitude(complexFloatArray)
+
+		fftRealShift := FftLogShift(fftReal) // --dev-- 20*math.Log10() and fft shift
+		fftReal=nil
+		
+		// allocate Fbin dimension of u16Spect, apply threshold, and load return values
+		u16Loader := make([]uint16, fftPoints)
+		for k,v := range fftRealShift {
+			if v < 0.0 {
+				fmt.Fprintf(os.Stderr, "--warning-- CreateU16Spect clipped float %v to uint16 0\n\r", v)
+				v = 0.0
+			}
+			if uint16(int(v)) < threshold {
+				u16Loader[k] = threshold
+				
+			} else {
+				u16Loader[k] = uint16(int(v))
+
+			}
+		}
+		fftRealShift=nil
+		// fmt.Println("--debug-- u16Loader:", u16Loader)
+		u16Spect[i] = ResizeArrayUint16(u16Loader, Fbins)		
+		// fmt.Println("--debug-- u16Spect[i]:", u16Spect[i])
+
+	} // end for i:=0; i< Tbins; i++
+
+	return u16Spect
+} // end func CreateU16SpectFromU16_sync
+
+// --obs-- deprecated dev code for backards compatability; use for < v0.3 only 
+// SpectrogramU16ToFile receives outnname for file, Tbins, Fbins, and [][]uint16 Spect, and
+// writes data to file 'outname' ins space separated mxn octave format.
+// Runs on raspi; default pico has no file system.
+func SpectrogramU16ToFile(outname string, Tbins, Fbins int, Spect [][]uint16) {
+	fileOut, err := os.Create(outname)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "panic: %s openning %s\n",
+			GetFunctionName(SpectrogramU16ToFile), outname)
+		panic(err)
+	}
+	defer fileOut.Close()
+	for i:=0; i<Tbins; i++ {
+		for j:=0; j<Fbins; j++ {
+			fmt.Fprintf(fileOut,"%v ", Spect[i][j])
+		}
+		fmt.Fprintf(fileOut,"\n")
+	}
+} // end func SpectrogramU16ToFile(outname string, Tbins, Fbins int, Spect [][]uint16) 
@@ -0,0 +1,95 @@
+// @file TinyGo/detectword/dsp/spect_test.go
+// @date 2026.10.18
+// @info overlapping fft framing: Tbins (BufSize-FrameLen)/Hop+1, and the frames an impulse
+//       lands in, hand computed from frame starts i*Hop
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package dsp
+
+import (
+	"reflect"
+	"testing"
+
+	"localhost/detectword/config"
+)
+
+func TestFrames(t *testing.T) {
+	tests := []struct {
+		bufSize, frameLen, hop int
+		tbins                  int
+	}{
+		{1040, 32, 16, 64},  // 0, 16, .. 1008; the last frame ends at 1040
+		{1024, 32, 16, 63},  // 1008 would end past 1024
+		{1048, 32, 8, 128},  // 0, 8, .. 1016
+		{1072, 64, 16, 64},  // 0, 16, .. 1008
+		{1024, 32, 0, 32},   // Hop 0 is FrameLen, not overlapping
+		{1024, 1024, 16, 1}, // one frame
+	}
+	for _, tt := range tests {
+		c := config.Default()
+		c.BufSize, c.FrameLen, c.Hop = tt.bufSize, tt.frameLen, tt.hop
+		c.DeriveTbins()
+		if c.Tbins != tt.tbins {
+			t.Errorf("BufSize %d FrameLen %d Hop %d: Tbins %d, want %d", tt.bufSize, tt.frameLen, tt.hop, c.Tbins, tt.tbins)
+		}
+	}
+	c := config.Default()
+	c.FrameLen = 0
+	c.DeriveTbins()
+	if c.Tbins != config.Default().Tbins {
+		t.Errorf("FrameLen 0: Tbins %d, want %d unchanged", c.Tbins, config.Default().Tbins)
+	}
+}
+
+// TestSpectFraming places an impulse in a quiet capture and checks which spectrogram rows
+// hold it; frame i covers samples i*16 to i*16+31 with BufSize 1040, FrameLen 32, Hop 16
+func TestSpectFraming(t *testing.T) {
+	c := config.Default()
+	c.BufSize, c.FrameLen, c.Hop, c.SpectThresh = 1040, 32, 16, 80
+	c.DeriveTbins()
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		at     int
+		frames []int
+	}{
+		{8, []int{0}},         // before frame 1 starts at 16
+		{520, []int{31, 32}},  // 496..527 and 512..543
+		{1032, []int{63}},     // the last frame, 1008..1039, not zero padded
+		{600, []int{36, 37}},  // 576..607 and 592..623
+		{1000, []int{61, 62}}, // 976..1007 and 992..1023
+	}
+	for _, spect := range []SpectKind{SpectComplex, SpectReal, SpectQ15} {
+		c.Spect = int(spect)
+		hamming := Hamming(c.FftPoints())
+		for _, tt := range tests {
+			u := make([]uint16, c.BufSize)
+			for i := range u {
+				u[i] = 0x8000
+			}
+			u[tt.at] = 0xF000
+			s, bIsNoise := CreateU16SpectConfig(u, hamming, c, NoiseThreshold)
+			if bIsNoise || len(s) != c.Tbins {
+				t.Fatalf("Spect %d impulse at %d: %d rows, noise %v; want %d rows", spect, tt.at, len(s), bIsNoise, c.Tbins)
+			}
+			var frames []int
+			for i, row := range s {
+				for _, v := range row {
+					if v > c.SpectThresh {
+						frames = append(frames, i)
+						break
+					}
+				}
+			}
+			if !reflect.DeepEqual(frames, tt.frames) {
+				t.Errorf("Spect %d impulse at %d: in frames %v, want %v", spect, tt.at, frames, tt.frames)
+			}
+		}
+	}
+}
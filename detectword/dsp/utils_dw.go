@@ -10,9 +10,13 @@
 
 // @date 2022.03.14 additions from reduce_array_avg dev
 // @date 2022.04.01 added Normalize_ac_threshold()
+// @date 2026.10.18 moved from package main to package dsp
+// @date 2026.10.18 added ResampleUint16()
+// @date 2026.10.18 ResizeArrayUint16() integer index; an identity for every n, as non power of 2
+//                  BufSize, FrameLen+(Tbins-1)*Hop, needs
 
 // @build: include file
-package main
+package dsp
 
 import (
 	"fmt"
@@ -194,7 +198,7 @@ func U16HexList2GoIncludeVar( filename, varname string) (GoInclude string) {
 
 	// write output include*.go file
 	outname := fmt.Sprintf("include_%s.go", varname)
-	fmt.Println("outfile:", outname, "\n")
+	fmt.Printf("outfile: %s\n\n", outname)
 	fileOut, err := os.Create(outname)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "panic: %s openning %s\n",
@@ -294,13 +298,47 @@ func ResizeArrayUint16(u0 []uint16, n int) []uint16 {
 	n0 := len(u0)
 	u1 := make([]uint16, n)
 	for i,_ := range u1 {
-		pct := float64(i)/float64(n)
-		u0Pos := int(math.Floor(pct*float64(n0)))
+		u0Pos := i*n0/n // floor(i/n * n0); in float, a sample early for some n, e.g. 1072
 		u1[i] = u0[u0Pos]
 	}
 	return u1
 } // end ResizeArrayUint16
 
+// ResampleUint16 receives samples 'u0' taken at 'rate0' Hz and returns them
+// resampled to 'rate1' Hz. Upsampling interpolates linearly; downsampling
+// averages the 'u0' samples under each output sample period, a boxcar
+// anti-alias filter roughly standing in for the pico's single pole LPF.
+func ResampleUint16(u0 []uint16, rate0, rate1 float64) []uint16 {
+	n0 := len(u0)
+	if n0 == 0 || rate0 <= 0 || rate1 <= 0 {
+		return []uint16{}
+	}
+	step := rate0/rate1 // u0 samples per u1 sample
+	u1 := make([]uint16, int(float64(n0)/step))
+	for i,_ := range u1 {
+		pos := float64(i)*step
+		if step > 1.0 { // downsample; average u0[pos-step/2 : pos+step/2]
+			lo := int(math.Ceil(pos-step/2)); hi := int(math.Floor(pos+step/2))
+			if lo < 0 { lo = 0 }
+			if hi > n0-1 { hi = n0-1 }
+			sum := 0.0
+			for j:=lo; j<=hi; j++ {
+				sum += float64(u0[j])
+			}
+			u1[i] = uint16(sum/float64(hi-lo+1) + 0.5)
+			continue
+		}
+		j := int(pos) // upsample; linear interpolation between u0[j] and u0[j+1]
+		frac := pos - float64(j)
+		if j+1 >= n0 {
+			u1[i] = u0[n0-1]
+			continue
+		}
+		u1[i] = uint16(float64(u0[j])*(1.0-frac) + float64(u0[j+1])*frac + 0.5)
+	}
+	return u1
+} // end ResampleUint16
+
 // IsPow2 returns true if N is a perfect power of 2 (1, 2, 4, 8, ...) and false otherwise.
 // Algorithm from: https://graphics.stanford.edu/~seander/bithacks.html#DetermineIfPowerOf2
 // https://github.com/ledyba/go-fft/blob/master/LICENSE
@@ -0,0 +1,98 @@
+// @file TinyGo/detectword/dsp/utils_dw_test.go
+// @date 2026.10.18
+// @info ResampleUint16 lengths and endpoints, up and down; ResizeArrayUint16 identity
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package dsp
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ramp returns 'n' samples 0, 16, 32, ...
+func ramp(n int) []uint16 {
+	u := make([]uint16, n)
+	for i := range u {
+		u[i] = uint16(i * 16)
+	}
+	return u
+}
+
+func TestResampleUint16Length(t *testing.T) {
+	pico := 1e6 / (300 + 16) // 3164.56 Hz
+	tests := []struct {
+		n            int
+		rate0, rate1 float64
+		want         int
+	}{
+		{64, 8000, 8000, 64},
+		{64, 1, 2, 128},
+		{64, 4, 1, 16},
+		{44100, 44100, pico, 3164}, // one second; the partial last period is dropped
+		{16000, 16000, pico, 3164},
+		{1000, pico, 44100, 13935},
+		{0, 44100, pico, 0},
+		{64, 0, pico, 0},
+		{64, 44100, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := len(ResampleUint16(ramp(tt.n), tt.rate0, tt.rate1)); got != tt.want {
+			t.Errorf("%d samples %.1f -> %.1f Hz: %d samples, want %d", tt.n, tt.rate0, tt.rate1, got, tt.want)
+		}
+	}
+}
+
+func TestResampleUint16(t *testing.T) {
+	u0 := ramp(64) // 0 .. 1008
+	if got := ResampleUint16(u0, 8000, 8000); !reflect.DeepEqual(got, u0) {
+		t.Errorf("same rate changed the samples: %v", got)
+	}
+
+	up := ResampleUint16(u0, 1, 2)
+	// even samples are u0, odd ones interpolate halfway; past u0[63] holds it
+	for i, want := range map[int]uint16{0: 0, 1: 8, 2: 16, 125: 1000, 126: 1008, 127: 1008} {
+		if up[i] != want {
+			t.Errorf("upsampled [%d] = %d, want %d", i, up[i], want)
+		}
+	}
+
+	down := ResampleUint16(u0, 4, 1)
+	// each sample averages u0 within +-2 of its time; the first is clipped to u0[0:3]
+	for i, want := range map[int]uint16{0: 16, 1: 64, 2: 128, 15: 960} {
+		if down[i] != want {
+			t.Errorf("downsampled [%d] = %d, want %d", i, down[i], want)
+		}
+	}
+
+	flat := make([]uint16, 1000)
+	for i := range flat {
+		flat[i] = 0x8000
+	}
+	for _, rate1 := range []float64{3164.56, 44100} {
+		for i, v := range ResampleUint16(flat, 16000, rate1) {
+			if v != 0x8000 {
+				t.Fatalf("constant input at %.0f Hz: [%d] = %#x, want 0x8000", rate1, i, v)
+			}
+		}
+	}
+}
+
+// TestResizeArrayUint16 checks a resize to the same length is an identity, for the non
+// power of 2 BufSize of overlapping frames too
+func TestResizeArrayUint16(t *testing.T) {
+	for n := 1; n <= 2080; n++ {
+		u := ramp(n)
+		if got := ResizeArrayUint16(u, n); !reflect.DeepEqual(got, u) {
+			t.Fatalf("ResizeArrayUint16 of %d samples to %d changed them", n, n)
+		}
+	}
+	if got, want := ResizeArrayUint16(ramp(4), 8), []uint16{0, 0, 16, 16, 32, 32, 48, 48}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ResizeArrayUint16 4 to 8 = %v, want %v", got, want)
+	}
+}
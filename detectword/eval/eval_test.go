@@ -0,0 +1,150 @@
+// @file TinyGo/detectword/eval/eval_test.go
+// @date 2026.10.18
+// @info Score, Confusion, Classes and ROC of a Result with a known confusion matrix, and a
+//       Run of a small fixture set of generated tone recordings
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package eval
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"localhost/detectword/config"
+	"localhost/detectword/match"
+	"localhost/detectword/wav"
+)
+
+// testResult returns a Result of words on and off decided with MinConfidence 0.5:
+//
+//	on rec  [100 500] match on       on rec  [300 350] ambiguous
+//	on rec  [600 200] match off      off rec [500 100] match off
+//	off rec short                    _noise  [250 300] ambiguous
+//	_noise  noise
+func testResult() *Result {
+	on, off, noise := &Item{Label: "on"}, &Item{Label: "off"}, &Item{Label: "_noise", Negative: true}
+	short := match.Detection{Outcome: match.Noise, Word: match.NoWord, Best: match.NoWord}
+	r := &Result{Refs: []match.RefWord{{Label: "on"}, {Label: "off"}}, Trials: []Trial{
+		{Item: on, Errs: []int{100, 500}},
+		{Item: on, Errs: []int{300, 350}},
+		{Item: on, Errs: []int{600, 200}},
+		{Item: off, Errs: []int{500, 100}},
+		{Item: off, Short: true, D: short},
+		{Item: noise, Errs: []int{250, 300}},
+		{Item: noise, D: short},
+	}}
+	c := config.Default()
+	c.NoiseMargin, c.MaxErr, c.MinConfidence = 0, 0, 0.5
+	r.Cfg = c
+	r.Decide(c)
+	return r
+}
+
+func TestScore(t *testing.T) {
+	r := testResult()
+	want := Score{Trials: 7, Positives: 5, Correct: 4, TrueAcc: 2, FalseAcc: 1, FalseRej: 2}
+	s := r.Score()
+	if s != want {
+		t.Fatalf("Score = %+v, want %+v", s, want)
+	}
+	if s.Accuracy() != 4.0/7 || s.FARate() != 1.0/7 || s.FRRate() != 2.0/5 {
+		t.Errorf("rates %v %v %v, want 4/7 1/7 2/5", s.Accuracy(), s.FARate(), s.FRRate())
+	}
+}
+
+func TestConfusion(t *testing.T) {
+	c := testResult().Confusion()
+	want := &Confusion{
+		Actual:   []string{"on", "off", "_noise"},
+		Detected: []string{"on", "off", Rejected},
+		Counts:   [][]int{{1, 1, 1}, {0, 1, 1}, {0, 0, 2}},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Fatalf("Confusion = %+v, want %+v", c, want)
+	}
+	wantClasses := []Class{
+		{Label: "on", Trials: 3, Detected: 1, TruePos: 1, Precision: 1, Recall: 1.0 / 3},
+		{Label: "off", Trials: 2, Detected: 2, TruePos: 1, Precision: 0.5, Recall: 0.5},
+	}
+	if cs := c.Classes(); !reflect.DeepEqual(cs, wantClasses) {
+		t.Errorf("Classes = %+v, want %+v", cs, wantClasses)
+	}
+	rows := c.Table().Rows
+	if want := [][]string{{"label", "on", "off", "none"}, {"on", "1", "1", "1"}, {"off", "0", "1", "1"},
+		{"_noise", "0", "0", "2"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("Table rows %q, want %q", rows, want)
+	}
+}
+
+func TestROC(t *testing.T) {
+	pts := testResult().ROC()
+	want := []Point{
+		{0, Score{Trials: 7, Positives: 5, Correct: 4, TrueAcc: 3, FalseAcc: 2, FalseRej: 1}},
+		{51, Score{Trials: 7, Positives: 5, Correct: 4, TrueAcc: 2, FalseAcc: 1, FalseRej: 2}},
+		{401, Score{Trials: 7, Positives: 5, Correct: 2, TrueAcc: 0, FalseAcc: 0, FalseRej: 5}},
+	}
+	if !reflect.DeepEqual(pts, want) {
+		t.Errorf("ROC = %+v, want %+v", pts, want)
+	}
+}
+
+// writeTone writes a 0.4 s 'hz' tone of peak 'amp' at 8 kHz to 'path'; amp 0 is silence
+func writeTone(t *testing.T, path string, hz, amp float64) {
+	pcm := make([]int16, 3200)
+	for i := range pcm {
+		pcm[i] = int16(amp * math.Sin(2*math.Pi*hz*float64(i)/8000))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := wav.WriteFile(path, pcm, 8000); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRunFixtures tones peak well over the fixed 0xBFFF noise threshold of config.Default()
+func TestRunFixtures(t *testing.T) {
+	dir := t.TempDir()
+	for i, amp := range []float64{20000, 22000, 24000, 26000, 28000} {
+		writeTone(t, filepath.Join(dir, "high", "take"+string(rune('0'+i))+".wav"), 1200, amp)
+		writeTone(t, filepath.Join(dir, "low", "take"+string(rune('0'+i))+".wav"), 300, amp)
+	}
+	writeTone(t, filepath.Join(dir, "_noise", "quiet.wav"), 0, 0)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0644) // not a word directory
+
+	s, err := LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Words, []string{"high", "low"}) || len(s.Items) != 11 {
+		t.Fatalf("LoadDir words %q, %d items; want high, low, 11 items", s.Words, len(s.Items))
+	}
+	c := config.Default()
+	c.Takes, c.MinAccepted = 2, 1
+	c.NoiseMargin = 0 // pure tones are far apart; keep them words
+	s.NoGate = true
+	s.Capture(c)
+	r, err := Run(s, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Enrolled) != 4 || len(r.Trials) != 7 {
+		t.Fatalf("%d enrolled, %d trials; want 4, 7", len(r.Enrolled), len(r.Trials))
+	}
+	if cm := r.Confusion().Counts; !reflect.DeepEqual(cm, [][]int{{3, 0, 0}, {0, 3, 0}, {0, 0, 1}}) {
+		t.Errorf("confusion %v, want every trial correct", cm)
+	}
+
+	os.RemoveAll(filepath.Join(dir, "low"))
+	if _, err := LoadDir(dir); err == nil {
+		t.Error("LoadDir of one word: no error")
+	}
+}
@@ -0,0 +1,239 @@
+// @file TinyGo/detectword/eval/report.go
+// @date 2026.10.18
+// @info evaluation reports of a Result; confusion matrix, per word precision and recall, and
+//       ROC/DET points as the decision margin varies, as Tables for text or CSV
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+
+package eval
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"localhost/detectword/match"
+)
+
+// Rejected is the detected column of trials detecting no word; noise, nomatch, ambiguous
+// or a short capture
+const Rejected = "none"
+
+// Table is a report; Rows[0] is the header
+type Table struct {
+	Title string
+	Rows  [][]string
+}
+
+// WriteText writes 't' with aligned columns, under its title
+func (t Table) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%s\n", t.Title); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, r := range t.Rows {
+		fmt.Fprintf(tw, "%s\t\n", strings.Join(r, "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes the rows of 't' as CSV
+func (t Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.WriteAll(t.Rows)
+	return cw.Error()
+}
+
+// Confusion counts trials by recording label, rows, and detected word, cols
+type Confusion struct {
+	Actual   []string // the words, then each negative label
+	Detected []string // the words, then Rejected
+	Counts   [][]int  // Counts[actual][detected]
+}
+
+// Confusion returns the confusion matrix of the trials of 'r'
+func (r *Result) Confusion() *Confusion {
+	c := &Confusion{}
+	for _, w := range r.Refs {
+		c.Actual = append(c.Actual, w.Label)
+		c.Detected = append(c.Detected, w.Label)
+	}
+	c.Detected = append(c.Detected, Rejected)
+	for _, t := range r.Trials {
+		if t.Item.Negative && index(c.Actual, t.Item.Label) < 0 {
+			c.Actual = append(c.Actual, t.Item.Label)
+		}
+	}
+	c.Counts = make([][]int, len(c.Actual))
+	for i := range c.Counts {
+		c.Counts[i] = make([]int, len(c.Detected))
+	}
+	for _, t := range r.Trials {
+		col := len(r.Refs) // Rejected
+		if t.Accepted() {
+			col = t.D.Word
+		}
+		c.Counts[index(c.Actual, t.Item.Label)][col]++
+	}
+	return c
+}
+
+// Table returns 'c' as a Table
+func (c *Confusion) Table() Table {
+	t := Table{Title: "confusion; rows recording label, cols detected word"}
+	t.Rows = append(t.Rows, append([]string{"label"}, c.Detected...))
+	for i, a := range c.Actual {
+		row := []string{a}
+		for _, n := range c.Counts[i] {
+			row = append(row, strconv.Itoa(n))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// Class is the precision and recall of detecting one word
+type Class struct {
+	Label     string
+	Trials    int // recordings of the word
+	Detected  int // trials detecting the word, any recording
+	TruePos   int // recordings of the word detecting it
+	Precision float64
+	Recall    float64
+}
+
+// Classes returns the precision and recall of each word of 'c'
+func (c *Confusion) Classes() []Class {
+	words := len(c.Detected) - 1
+	cs := make([]Class, words)
+	for w := range cs {
+		cl := Class{Label: c.Detected[w], TruePos: c.Counts[w][w]}
+		for _, n := range c.Counts[w] {
+			cl.Trials += n
+		}
+		for a := range c.Actual {
+			cl.Detected += c.Counts[a][w]
+		}
+		cl.Precision = ratio(cl.TruePos, cl.Detected)
+		cl.Recall = ratio(cl.TruePos, cl.Trials)
+		cs[w] = cl
+	}
+	return cs
+}
+
+// ClassTable returns 'cs' as a Table
+func ClassTable(cs []Class) Table {
+	t := Table{Title: "per word precision and recall"}
+	t.Rows = append(t.Rows, []string{"word", "trials", "detected", "true", "precision", "recall"})
+	for _, c := range cs {
+		t.Rows = append(t.Rows, []string{c.Label, strconv.Itoa(c.Trials), strconv.Itoa(c.Detected),
+			strconv.Itoa(c.TruePos), fmt.Sprintf("%.3f", c.Precision), fmt.Sprintf("%.3f", c.Recall)})
+	}
+	return t
+}
+
+// Point is a ROC/DET operating point; the Score of the trials accepting a word only with a
+// margin of at least MinMargin
+type Point struct {
+	MinMargin int
+	Score
+}
+
+// TARate is the true accept rate, the fraction of word recordings detecting their word
+func (p Point) TARate() float64 { return ratio(p.TrueAcc, p.Positives) }
+
+// ROC returns the operating points of 'r' as the decision margin varies, from accepting any
+// margin to accepting none. The minimum margin stands in for r.Cfg.MinConfidence; the best
+// word is otherwise decided as match.Decide does, NoiseMargin and MaxErr included.
+func (r *Result) ROC() []Point {
+	p := match.DetectParamsOf(r.Cfg)
+	p.MinConfidence = 0
+	margins := []int{0}
+	ds := make([]match.Detection, len(r.Trials))
+	for i, t := range r.Trials {
+		ds[i] = t.D
+		if t.Errs != nil {
+			ds[i] = match.Decide(t.Errs, r.Refs, p)
+		}
+		if ds[i].Outcome == match.Match { // rejected from a minimum of its margin + 1
+			margins = append(margins, ds[i].Margin+1)
+		}
+	}
+	sort.Ints(margins)
+	var pts []Point
+	for k, m := range margins {
+		if k > 0 && m == margins[k-1] {
+			continue
+		}
+		at := Result{Refs: r.Refs, Trials: make([]Trial, len(r.Trials))}
+		for i, t := range r.Trials {
+			t.D = ds[i]
+			if t.D.Outcome == match.Match && t.D.Margin < m {
+				t.D = match.Detection{Outcome: match.Ambiguous, Word: match.NoWord, Best: t.D.Best,
+					Err: t.D.Err, Margin: t.D.Margin, Confidence: t.D.Confidence, Errs: t.D.Errs}
+			}
+			at.Trials[i] = t
+		}
+		pts = append(pts, Point{MinMargin: m, Score: at.Score()})
+	}
+	return pts
+} // end func ROC
+
+// ROCTable returns 'pts' as a Table; ROC plots ta against fa, DET fr against fa
+func ROCTable(pts []Point) Table {
+	t := Table{Title: "roc/det as the minimum decision margin varies"}
+	t.Rows = append(t.Rows, []string{"minmargin", "accuracy", "ta", "fa", "fr"})
+	for _, p := range pts {
+		t.Rows = append(t.Rows, []string{strconv.Itoa(p.MinMargin), fmt.Sprintf("%.3f", p.Accuracy()),
+			fmt.Sprintf("%.3f", p.TARate()), fmt.Sprintf("%.3f", p.FARate()), fmt.Sprintf("%.3f", p.FRRate())})
+	}
+	return t
+}
+
+// TrialTable returns each trial of 'r' as a Table
+func (r *Result) TrialTable() Table {
+	t := Table{Title: "trials"}
+	head := []string{"file", "label", "outcome", "word", "err", "margin", "conf"}
+	for _, w := range r.Refs {
+		head = append(head, w.Label)
+	}
+	t.Rows = append(t.Rows, head)
+	for _, tr := range r.Trials {
+		outcome := tr.D.Outcome.String()
+		if tr.Short {
+			outcome = "short"
+		}
+		word := "-" // best word, match or not
+		if tr.D.Best != match.NoWord {
+			word = r.Refs[tr.D.Best].Label
+		}
+		row := []string{tr.Item.Path, tr.Item.Label, outcome, word, strconv.Itoa(tr.D.Err),
+			strconv.Itoa(tr.D.Margin), fmt.Sprintf("%.3f", tr.D.Confidence)}
+		for i := range r.Refs {
+			e := ""
+			if i < len(tr.Errs) {
+				e = strconv.Itoa(tr.Errs[i])
+			}
+			row = append(row, e)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+func index(s []string, v string) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,160 @@
+// @file TinyGo/detectword/eval/run.go
+// @date 2026.10.18
+// @info runs a captured Set through the firmware pipeline; enrolls each word from its first
+//       recordings, detects the rest and scores the detections
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+
+package eval
+
+import (
+	"fmt"
+
+	"localhost/detectword/config"
+	"localhost/detectword/dsp"
+	"localhost/detectword/match"
+)
+
+// Trial is the detection of one recording
+type Trial struct {
+	Item  *Item
+	Short bool  // capture shorter than MinWordLen; not processed, as on the pico
+	Errs  []int // error to each reference; nil when Short or noise
+	D     match.Detection
+}
+
+// Accepted returns whether the trial detected a word
+func (t Trial) Accepted() bool {
+	return t.D.Outcome == match.Match
+}
+
+// Correct returns whether the trial detected its own word, or no word for a negative
+func (t Trial) Correct() bool {
+	if t.Item.Negative {
+		return !t.Accepted()
+	}
+	return t.Accepted() && t.D.Label == t.Item.Label
+}
+
+// Result is a Run of a Set with a Config
+type Result struct {
+	Cfg      config.Config
+	Refs     []match.RefWord // Set.Words order
+	Enrolled []*Item         // recordings enrolled, not detected
+	Trials   []Trial
+}
+
+// Run enrolls each word of 's' from its first c.Takes recordings, as match.EnrollWord does
+// the firmware's takes, and detects every other recording with match.DetectConfig; 's' has
+// been captured with the capture fields of 'c'
+func Run(s *Set, c config.Config) (*Result, error) {
+	r := &Result{Cfg: c, Refs: make([]match.RefWord, len(s.Words))}
+	hamming := dsp.Hamming(c.FftPoints())
+	enroll := match.EnrollParamsOf(c)
+	for w, label := range s.Words {
+		var takes [][][]int
+		for _, it := range s.Items {
+			if it.Label != label || len(takes) == c.Takes {
+				continue
+			}
+			r.Enrolled = append(r.Enrolled, it)
+			if len(it.Capture) < c.MinWordLen {
+				continue
+			}
+			reduced, _, _, bIsNoise := match.EnrollTake(it.Capture, hamming, it.NoiseThresh, enroll)
+			if !bIsNoise {
+				takes = append(takes, reduced)
+			}
+		}
+		ref, _, err := match.EnrollWord(label, takes, enroll)
+		if err != nil {
+			return nil, fmt.Errorf("word %s: %d usable takes: %v", label, len(takes), err)
+		}
+		r.Refs[w] = ref
+	}
+	enrolled := make(map[*Item]bool, len(r.Enrolled))
+	for _, it := range r.Enrolled {
+		enrolled[it] = true
+	}
+	for _, it := range s.Items {
+		if enrolled[it] {
+			continue
+		}
+		t := Trial{Item: it}
+		if len(it.Capture) < c.MinWordLen {
+			t.Short = true
+			t.D = match.Detection{Outcome: match.Noise, Word: match.NoWord, Best: match.NoWord}
+			r.Trials = append(r.Trials, t)
+			continue
+		}
+		U16Spect, bIsNoise := dsp.CreateU16SpectConfig(it.Capture, hamming, c, it.NoiseThresh)
+		t.D = match.DetectConfig(U16Spect, bIsNoise, r.Refs, c)
+		t.Errs = t.D.Errs
+		r.Trials = append(r.Trials, t)
+	}
+	return r, nil
+} // end func Run
+
+// Decide decides every trial again with the match.Detect decision thresholds of 'c',
+// NoiseMargin, MinConfidence and MaxErr, without repeating the spectrograms and reductions
+func (r *Result) Decide(c config.Config) {
+	p := match.DetectParamsOf(c)
+	for i := range r.Trials {
+		if r.Trials[i].Errs != nil {
+			r.Trials[i].D = match.Decide(r.Trials[i].Errs, r.Refs, p)
+		}
+	}
+	r.Cfg.NoiseMargin, r.Cfg.MinConfidence, r.Cfg.MaxErr = c.NoiseMargin, c.MinConfidence, c.MaxErr
+}
+
+// Score counts the outcomes of a Result
+type Score struct {
+	Trials    int
+	Positives int // trials of a word recording
+	Correct   int // own word detected, or no word for a negative
+	TrueAcc   int // own word detected for a word recording
+	FalseAcc  int // a word detected other than the recording's; any word for a negative
+	FalseRej  int // no word detected for a word recording
+}
+
+// Score returns the Score of the trials of 'r'
+func (r *Result) Score() (s Score) {
+	for _, t := range r.Trials {
+		s.Trials++
+		if !t.Item.Negative {
+			s.Positives++
+		}
+		switch {
+		case t.Correct():
+			s.Correct++
+			if !t.Item.Negative {
+				s.TrueAcc++
+			}
+		case t.Accepted():
+			s.FalseAcc++
+		default:
+			s.FalseRej++
+		}
+	}
+	return s
+}
+
+// Accuracy is the fraction of trials correct
+func (s Score) Accuracy() float64 { return ratio(s.Correct, s.Trials) }
+
+// FARate is the false accept rate, the fraction of trials detecting a wrong word
+func (s Score) FARate() float64 { return ratio(s.FalseAcc, s.Trials) }
+
+// FRRate is the false reject rate, the fraction of word recordings detecting no word
+func (s Score) FRRate() float64 { return ratio(s.FalseRej, s.Positives) }
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
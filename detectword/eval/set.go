@@ -0,0 +1,140 @@
+// @file TinyGo/detectword/eval/set.go
+// @date 2026.10.18
+// @info labelled recording sets; a directory per word of wav recordings, '_' directories of
+//       negatives, other words and noise, captured as the firmware would
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+
+package eval
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/dsp"
+	"localhost/detectword/wav"
+)
+
+// NegativePrefix starts the directory name of negatives, e.g. _noise, _other
+const NegativePrefix = "_"
+
+// Item is one labelled recording
+type Item struct {
+	Label    string // word, or the negative directory name
+	Path     string
+	Negative bool
+
+	// Capture and NoiseThresh are set by Set.Capture
+	Capture     []uint16 // pico adc samples
+	NoiseThresh uint16   // capture noise threshold; relative to the noise floor with Config.AdaptNoise
+
+	pcm  []int16
+	rate int
+}
+
+// Set is a labelled recording set
+type Set struct {
+	Dir    string
+	Words  []string // word labels, sorted; the reference words, in order
+	Items  []*Item  // by directory then file name
+	Gain   float64  // applied to the pcm samples before adc scaling; 1 is none
+	NoGate bool     // skip adc.CaptureVAD; captures are the whole recording up to BufSize
+}
+
+// LoadDir reads the .wav recordings of each subdirectory of 'dir'. A subdirectory is a word
+// labelled by its name, or negatives when the name starts with NegativePrefix.
+func LoadDir(dir string) (*Set, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &Set{Dir: dir, Gain: 1}
+	for _, e := range entries { // ReadDir sorts by name
+		if !e.IsDir() {
+			continue
+		}
+		label := e.Name()
+		negative := strings.HasPrefix(label, NegativePrefix)
+		files, err := filepath.Glob(filepath.Join(dir, label, "*.wav"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		if len(files) == 0 {
+			continue
+		}
+		if !negative {
+			s.Words = append(s.Words, label)
+		}
+		for _, f := range files {
+			pcm, rate, err := wav.ReadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			s.Items = append(s.Items, &Item{Label: label, Path: f, Negative: negative, pcm: pcm, rate: rate})
+		}
+	}
+	if len(s.Words) < 2 {
+		return nil, fmt.Errorf("%s: %d word directories; want at least 2", dir, len(s.Words))
+	}
+	return s, nil
+} // end func LoadDir
+
+// Capture converts each recording to pico adc samples at the sample rate of 'c', the achieved
+// rate with c.Clocked, and runs it through the firmware capture loop, adc.CaptureVAD, in Items
+// order; with c.AdaptNoise one noise floor is tracked across the recordings, as across
+// captures on the pico
+func (s *Set) Capture(c config.Config) {
+	noise := adc.NewNoiseFloor(c.Noise)
+	vp := c.VAD
+	if c.AdaptNoise {
+		vp.Noise = noise
+	}
+	for _, it := range s.Items {
+		uBuf := dsp.ResampleUint16(wav.Pcm2Adc(applyGain(it.pcm, s.Gain), wav.AdcMid), float64(it.rate), c.SampleRate())
+		if s.NoGate {
+			if len(uBuf) > c.BufSize {
+				uBuf = uBuf[:c.BufSize]
+			}
+		} else {
+			var sampler adc.Sampler = adc.NewReplaySampler(uBuf)
+			if c.Clocked {
+				sampler = adc.NewClockedSampler(sampler, c.SampleRate())
+			}
+			uBuf, _ = adc.CaptureVAD(sampler, adc.NullIndicator{}, c.BufSize, c.SleepTime, c.PreTriggerMs, vp)
+		}
+		it.Capture = uBuf
+		it.NoiseThresh = dsp.NoiseThreshold
+		if c.AdaptNoise {
+			it.NoiseThresh = noise.PeakThreshold(vp.DC)
+		}
+	}
+} // end func Capture
+
+// applyGain scales 'pcm' by 'gain', clipping at the int16 range
+func applyGain(pcm []int16, gain float64) []int16 {
+	if gain == 1.0 {
+		return pcm
+	}
+	out := make([]int16, len(pcm))
+	for i, v := range pcm {
+		f := float64(v) * gain
+		if f < -32768 {
+			f = -32768
+		}
+		if f > 32767 {
+			f = 32767
+		}
+		out[i] = int16(f)
+	}
+	return out
+}
@@ -0,0 +1,230 @@
+// @file TinyGo/detectword/frame/decode.go
+// @date 2026.10.18
+// @info host decoder of frame.go frames; resynchronizes on the sync bytes after noise, text
+//       or a crc mismatch, and decodes each payload type
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 Config decodes the 19 ParamNames, or the 10 LegacyParams of earlier firmware
+
+// @build: go build
+
+package frame
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/match"
+)
+
+var ErrCorrupt = errors.New("frame: payload corrupt")
+
+// Frame is one decoded frame
+type Frame struct {
+	Type    Type
+	Payload []byte
+}
+
+// Decoder reads frames from a byte stream
+type Decoder struct {
+	Skipped int // bytes outside frames, e.g. console text, and of frames failing the crc
+	BadCrc  int // frames failing the crc
+
+	r *bufio.Reader
+}
+
+// NewDecoder returns a Decoder on 'r', a serial device or a recorded stream
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReaderSize(r, headerSize+MaxPayload+crcSize)} // Peek holds any frame
+}
+
+// Next returns the next frame with a good crc; io.EOF at the end of the stream, including
+// within a frame cut short
+func (d *Decoder) Next() (f Frame, err error) {
+	for {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return f, err
+		}
+		if b != Sync0 {
+			d.Skipped++
+			continue
+		}
+		next, err := d.r.Peek(1)
+		if err != nil {
+			return f, eof(err)
+		}
+		if next[0] != Sync1 {
+			d.Skipped++
+			continue
+		}
+		hdr, err := d.r.Peek(1 + 3) // sync1, type, length
+		if err != nil {
+			return f, eof(err)
+		}
+		n := int(binary.LittleEndian.Uint16(hdr[2:4]))
+		body, err := d.r.Peek(1 + 3 + n + crcSize)
+		if err != nil {
+			return f, eof(err)
+		}
+		if f, ok := d.check(body); ok {
+			d.r.Discard(len(body))
+			return f, nil
+		}
+		// bad crc; the sync may have been payload, so resync from the byte after it
+	}
+} // end func Next
+
+// check returns the frame of 'body', the bytes after Sync0, when its crc is good
+func (d *Decoder) check(body []byte) (f Frame, ok bool) {
+	n := len(body) - crcSize
+	want := binary.LittleEndian.Uint16(body[n:])
+	if Crc16(0xFFFF, body[1:n]) != want {
+		d.BadCrc++
+		d.Skipped++
+		return f, false
+	}
+	payload := make([]byte, n-4)
+	copy(payload, body[4:n])
+	return Frame{Type: Type(body[1]), Payload: payload}, true
+}
+
+func eof(err error) error {
+	if err == io.ErrUnexpectedEOF {
+		return io.EOF
+	}
+	return err
+}
+
+// payload reads little endian values from a Payload; past the end it sets err
+type payload struct {
+	b   []byte
+	err error
+}
+
+func (p *payload) bytes(n int) []byte {
+	if p.err != nil || len(p.b) < n {
+		p.err = ErrCorrupt
+		return make([]byte, n)
+	}
+	b := p.b[:n]
+	p.b = p.b[n:]
+	return b
+}
+
+func (p *payload) u8() int  { return int(p.bytes(1)[0]) }
+func (p *payload) i8() int  { return int(int8(p.bytes(1)[0])) }
+func (p *payload) u16() int { return int(binary.LittleEndian.Uint16(p.bytes(2))) }
+func (p *payload) u32() int { return int(binary.LittleEndian.Uint32(p.bytes(4))) }
+func (p *payload) i32() int { return int(int32(binary.LittleEndian.Uint32(p.bytes(4)))) }
+
+// done returns the error of a payload read to its end
+func (p *payload) done() error {
+	if p.err == nil && len(p.b) != 0 {
+		return ErrCorrupt
+	}
+	return p.err
+}
+
+// Config returns config.Default() with the ParamNames fields of a TypeParams frame; the
+// fields a frame does not carry, VAD, noise floor, detection and enrollment, are Default()'s,
+// as are FrameLen .. Deltas of the LegacyParams payload of earlier firmware
+func (f Frame) Config() (c config.Config, err error) {
+	p := payload{b: f.Payload}
+	c = config.Default()
+	n := len(ParamNames)
+	if len(p.b) == 2*LegacyParams {
+		n = LegacyParams
+	}
+	vals := ParamValues(c)
+	for i := 0; i < n; i++ {
+		vals[i] = p.u16()
+	}
+	SetParams(&c, vals)
+	return c, p.done()
+}
+
+// Samples returns the index of the first sample, and the samples, of a TypeSamples frame
+func (f Frame) Samples() (first int, samples []uint16, err error) {
+	p := payload{b: f.Payload}
+	first = p.u16()
+	samples = make([]uint16, len(p.b)/2)
+	for i := range samples {
+		samples[i] = uint16(p.u16())
+	}
+	return first, samples, p.done()
+}
+
+// Spect returns the spectrogram of a TypeSpect frame
+func (f Frame) Spect() (spect [][]uint16, err error) {
+	p := payload{b: f.Payload}
+	rows, cols := p.u16(), p.u16()
+	if p.err == nil && len(p.b) != 2*rows*cols {
+		return nil, ErrCorrupt
+	}
+	spect = make([][]uint16, rows)
+	for i := range spect {
+		spect[i] = make([]uint16, cols)
+		for j := range spect[i] {
+			spect[i][j] = uint16(p.u16())
+		}
+	}
+	return spect, p.done()
+}
+
+// Pool returns the pooled array of a TypePool1 or TypePool2 frame
+func (f Frame) Pool() (pool [][]int, err error) {
+	p := payload{b: f.Payload}
+	rows, cols := p.u16(), p.u16()
+	if p.err == nil && len(p.b) != 4*rows*cols {
+		return nil, ErrCorrupt
+	}
+	pool = make([][]int, rows)
+	for i := range pool {
+		pool[i] = make([]int, cols)
+		for j := range pool[i] {
+			pool[i][j] = p.i32()
+		}
+	}
+	return pool, p.done()
+}
+
+// NoiseReport is the payload of a TypeNoise frame
+type NoiseReport struct {
+	Energy, Rms, Frames int // adc.NoiseFloor
+	Start, Stop         int // VAD thresholds
+	Peak                int // capture noise threshold
+	Seg                 adc.Segment
+}
+
+// Noise returns the noise report of a TypeNoise frame
+func (f Frame) Noise() (n NoiseReport, err error) {
+	p := payload{b: f.Payload}
+	n.Energy, n.Rms, n.Frames = p.i32(), p.u16(), p.u32()
+	n.Start, n.Stop, n.Peak = p.i32(), p.i32(), p.u16()
+	n.Seg.Start, n.Seg.End = p.u16(), p.u16()
+	n.Seg.Reason = adc.VADReason(p.u8())
+	n.Seg.Clicks = p.u16()
+	return n, p.done()
+}
+
+// Detection returns the detection of a TypeDetection frame; Label is not sent
+func (f Frame) Detection() (d match.Detection, err error) {
+	p := payload{b: f.Payload}
+	d.Outcome = match.Outcome(p.u8())
+	d.Word, d.Best = p.i8(), p.i8()
+	d.Err, d.Margin = p.i32(), p.i32()
+	d.Confidence = float64(p.u16()) / 10000
+	d.Errs = make([]int, p.u8())
+	for i := range d.Errs {
+		d.Errs[i] = p.i32()
+	}
+	return d, p.done()
+}
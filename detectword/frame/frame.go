@@ -0,0 +1,342 @@
+// @file TinyGo/detectword/frame/frame.go
+// @date 2026.10.18
+// @info framed binary diagnostics; samples, spectrograms, pooled arrays, noise floor and
+//       detection events as checksummed frames, in place of the tagged decimal captureDiags
+//       text, which is slow at 1024+ samples and fragile to parse
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 TypeParams carries FrameLen, Hop, Spect and the mel front end, ParamNames
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+// Frame layout, little endian:
+//
+//	offset size
+//	0      2    sync 0xA5 0x5A
+//	2      1    message type
+//	3      2    payload length in bytes
+//	5      ...  payload
+//	5+len  2    crc16 (CCITT, init 0xFFFF) of type, length and payload
+//
+// payloads:
+//
+//	TypeParams     19 uint16, ParamNames: BufSize, SleepTime, GetUs, Tbins, Fbins, VBlocks,
+//	               HBlocks, VBlocks2, HBlocks2, SpectThresh, FrameLen, Hop, Spect, MelFilters,
+//	               MelLowHz, MelHighHz, MFCCs, Lifter, Deltas; starts a transmission. The
+//	               first 10 alone are the payload of earlier firmware
+//	TypeSamples    uint16 index of the first sample, then uint16 samples; a capture is
+//	               sent in frames of up to MaxSamples
+//	TypeSpect      uint16 rows, uint16 cols, then rows x cols uint16
+//	TypePool1/2    uint16 rows, uint16 cols, then rows x cols int32
+//	TypeNoise      int32 floor energy, uint16 rms, uint32 frames, int32 start, int32 stop,
+//	               uint16 peak threshold, uint16 segment start, uint16 segment end,
+//	               uint8 segment reason, uint16 clicks
+//	TypeDetection  uint8 outcome, int8 word, int8 best, int32 err, int32 margin, uint16
+//	               confidence x 10000, uint8 count, then count int32 errs
+//	TypeEnd        empty; ends a transmission
+
+package frame
+
+import (
+	"errors"
+	"io"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/match"
+)
+
+// Sync are the first bytes of every frame
+const (
+	Sync0 = 0xA5
+	Sync1 = 0x5A
+)
+
+const headerSize = 5 // sync, type, length
+const crcSize = 2
+
+// MaxPayload is the largest payload a frame length holds
+const MaxPayload = 0xFFFF
+
+// MaxSamples are the samples per TypeSamples frame; 1 KB frames resync quickly after loss
+const MaxSamples = 512
+
+// Type is a frame message type
+type Type uint8
+
+const (
+	TypeParams Type = iota + 1
+	TypeSamples
+	TypeSpect
+	TypePool1
+	TypePool2
+	TypeNoise
+	TypeDetection
+	TypeEnd
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeParams:
+		return "params"
+	case TypeSamples:
+		return "samples"
+	case TypeSpect:
+		return "spect"
+	case TypePool1:
+		return "pool1"
+	case TypePool2:
+		return "pool2"
+	case TypeNoise:
+		return "noise"
+	case TypeDetection:
+		return "detection"
+	case TypeEnd:
+		return "end"
+	}
+	return "unknown"
+}
+
+var ErrTooLarge = errors.New("frame: payload too large")
+
+// Crc16 updates CCITT crc 'crc' with 'b'; start with 0xFFFF
+func Crc16(crc uint16, b []byte) uint16 {
+	for _, v := range b {
+		crc ^= uint16(v) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Encoder writes frames to W without allocating; payloads are staged through a small
+// buffer, so a frame is written in pieces as it is encoded
+type Encoder struct {
+	W io.Writer
+
+	buf  [64]byte
+	n    int    // bytes staged in buf
+	skip int    // staged bytes outside the crc, the sync
+	crc  uint16 // crc of the frame so far
+	err  error  // first write error; later writes are skipped
+}
+
+// NewEncoder returns an Encoder writing to 'w', e.g. machine.Serial
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{W: w}
+}
+
+// Err returns the first write error
+func (e *Encoder) Err() error { return e.err }
+
+// begin starts a frame of type 't' with a payload of 'n' bytes
+func (e *Encoder) begin(t Type, n int) bool {
+	if n > MaxPayload {
+		if e.err == nil {
+			e.err = ErrTooLarge
+		}
+		return false
+	}
+	e.buf[0], e.buf[1] = Sync0, Sync1
+	e.n, e.skip = 2, 2
+	e.crc = 0xFFFF
+	e.u8(uint8(t))
+	e.u16(uint16(n))
+	return true
+}
+
+// end writes the crc and flushes the frame
+func (e *Encoder) end() error {
+	e.flush()
+	crc := e.crc
+	e.buf[0], e.buf[1] = byte(crc), byte(crc>>8)
+	e.n = 2
+	e.write()
+	return e.err
+}
+
+// put stages 'b', counting it in the crc
+func (e *Encoder) put(b ...byte) {
+	if e.n+len(b) > len(e.buf) {
+		e.flush()
+	}
+	copy(e.buf[e.n:], b)
+	e.n += len(b)
+}
+
+func (e *Encoder) u8(v uint8)   { e.put(v) }
+func (e *Encoder) u16(v uint16) { e.put(byte(v), byte(v>>8)) }
+func (e *Encoder) u32(v uint32) { e.put(byte(v), byte(v>>8), byte(v>>16), byte(v>>24)) }
+func (e *Encoder) i32(v int)    { e.u32(uint32(int32(v))) }
+
+// flush adds the staged bytes to the crc and writes them
+func (e *Encoder) flush() {
+	e.crc = Crc16(e.crc, e.buf[e.skip:e.n])
+	e.skip = 0
+	e.write()
+}
+
+func (e *Encoder) write() {
+	if e.err == nil && e.n > 0 {
+		_, e.err = e.W.Write(e.buf[:e.n])
+	}
+	e.n = 0
+}
+
+// ParamNames are the Config fields of a TypeParams frame, in payload order, and the 'name
+// value' lines of the captureDiags params file; the capture, spectrogram and reduction
+// fields the diagnostic array shapes follow from, and GetUs
+var ParamNames = []string{"BufSize", "SleepTime", "GetUs", "Tbins", "Fbins",
+	"VBlocks", "HBlocks", "VBlocks2", "HBlocks2", "SpectThresh",
+	"FrameLen", "Hop", "Spect", "MelFilters", "MelLowHz", "MelHighHz", "MFCCs", "Lifter", "Deltas"}
+
+// LegacyParams are the ParamNames of earlier firmware, its whole TypeParams payload
+const LegacyParams = 10
+
+// ParamValues returns the ParamNames fields of 'c', in order
+func ParamValues(c config.Config) []int {
+	return []int{c.BufSize, c.SleepTime, c.GetUs, c.Tbins, c.Fbins,
+		c.VBlocks, c.HBlocks, c.VBlocks2, c.HBlocks2, int(c.SpectThresh),
+		c.FrameLen, c.Hop, c.Spect, c.MelFilters, c.MelLowHz, c.MelHighHz, c.MFCCs, c.Lifter, c.Deltas}
+}
+
+// SetParams sets the ParamNames fields of 'c' to 'vals', in order; the inverse of ParamValues
+func SetParams(c *config.Config, vals []int) {
+	c.BufSize, c.SleepTime, c.GetUs, c.Tbins, c.Fbins = vals[0], vals[1], vals[2], vals[3], vals[4]
+	c.VBlocks, c.HBlocks, c.VBlocks2, c.HBlocks2 = vals[5], vals[6], vals[7], vals[8]
+	c.SpectThresh = uint16(vals[9])
+	c.FrameLen, c.Hop, c.Spect = vals[10], vals[11], vals[12]
+	c.MelFilters, c.MelLowHz, c.MelHighHz, c.MFCCs, c.Lifter, c.Deltas = vals[13], vals[14], vals[15], vals[16], vals[17], vals[18]
+}
+
+// Params writes the TypeParams frame of 'c', starting a transmission
+func (e *Encoder) Params(c config.Config) error {
+	if !e.begin(TypeParams, 2*len(ParamNames)) {
+		return e.err
+	}
+	for _, v := range ParamValues(c) {
+		e.u16(uint16(v))
+	}
+	return e.end()
+}
+
+// Samples writes 'samples' as TypeSamples frames of up to MaxSamples
+func (e *Encoder) Samples(samples []uint16) error {
+	first := 0
+	for { // an empty capture is one empty frame
+		chunk := samples[first:]
+		if len(chunk) > MaxSamples {
+			chunk = chunk[:MaxSamples]
+		}
+		if !e.begin(TypeSamples, 2+2*len(chunk)) {
+			return e.err
+		}
+		e.u16(uint16(first))
+		for _, v := range chunk {
+			e.u16(v)
+		}
+		if err := e.end(); err != nil {
+			return err
+		}
+		first += len(chunk)
+		if first >= len(samples) {
+			return nil
+		}
+	}
+}
+
+// Spect writes a TypeSpect frame of spectrogram 'spect'
+func (e *Encoder) Spect(spect [][]uint16) error {
+	rows, cols := len(spect), 0
+	if rows > 0 {
+		cols = len(spect[0])
+	}
+	if !e.begin(TypeSpect, 4+2*rows*cols) {
+		return e.err
+	}
+	e.u16(uint16(rows))
+	e.u16(uint16(cols))
+	for i := range spect {
+		for j := 0; j < cols; j++ {
+			e.u16(spect[i][j])
+		}
+	}
+	return e.end()
+}
+
+// Pool writes pooled array 'pool' as a frame of type 't', TypePool1 or TypePool2
+func (e *Encoder) Pool(t Type, pool [][]int) error {
+	rows, cols := len(pool), 0
+	if rows > 0 {
+		cols = len(pool[0])
+	}
+	if !e.begin(t, 4+4*rows*cols) {
+		return e.err
+	}
+	e.u16(uint16(rows))
+	e.u16(uint16(cols))
+	for i := range pool {
+		for j := 0; j < cols; j++ {
+			e.i32(pool[i][j])
+		}
+	}
+	return e.end()
+}
+
+// Noise writes a TypeNoise frame of noise floor 'noise', capture segment 'seg' and the
+// capture noise threshold 'peak'
+func (e *Encoder) Noise(noise *adc.NoiseFloor, seg adc.Segment, peak uint16) error {
+	if !e.begin(TypeNoise, 27) {
+		return e.err
+	}
+	start, stop := noise.Thresholds()
+	e.i32(noise.Energy)
+	e.u16(uint16(noise.Rms()))
+	e.u32(uint32(noise.Frames))
+	e.i32(start)
+	e.i32(stop)
+	e.u16(peak)
+	e.u16(uint16(seg.Start))
+	e.u16(uint16(seg.End))
+	e.u8(uint8(seg.Reason))
+	e.u16(uint16(seg.Clicks))
+	return e.end()
+}
+
+// Detection writes a TypeDetection frame of 'd'
+func (e *Encoder) Detection(d match.Detection) error {
+	n := len(d.Errs)
+	if n > 255 {
+		n = 255
+	}
+	if !e.begin(TypeDetection, 14+4*n) {
+		return e.err
+	}
+	e.u8(uint8(d.Outcome))
+	e.u8(uint8(int8(d.Word)))
+	e.u8(uint8(int8(d.Best)))
+	e.i32(d.Err)
+	e.i32(d.Margin)
+	e.u16(uint16(d.Confidence * 10000))
+	e.u8(uint8(n))
+	for _, v := range d.Errs[:n] {
+		e.i32(v)
+	}
+	return e.end()
+}
+
+// End writes a TypeEnd frame, ending a transmission
+func (e *Encoder) End() error {
+	if !e.begin(TypeEnd, 0) {
+		return e.err
+	}
+	return e.end()
+}
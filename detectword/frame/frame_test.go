@@ -0,0 +1,206 @@
+// @file TinyGo/detectword/frame/frame_test.go
+// @date 2026.10.18
+// @info Encoder to Decoder round trip of each frame type, the crc16, and resync after
+//       garbage and corrupt frames
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package frame
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"localhost/adc"
+	"localhost/detectword/config"
+	"localhost/detectword/match"
+)
+
+func TestCrc16(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint16
+	}{
+		{"", 0xFFFF},
+		{"123456789", 0x29B1}, // CRC-16/CCITT-FALSE check value
+		{"A", 0xB915},
+	}
+	for _, tt := range tests {
+		if got := Crc16(0xFFFF, []byte(tt.in)); got != tt.want {
+			t.Errorf("Crc16(%q) = %#04x, want %#04x", tt.in, got, tt.want)
+		}
+	}
+	if got := Crc16(Crc16(0xFFFF, []byte("1234")), []byte("56789")); got != 0x29B1 {
+		t.Errorf("Crc16 in two parts = %#04x, want 0x29b1", got)
+	}
+}
+
+// frameOf returns the bytes of the frames 'enc' writes
+func frameOf(t *testing.T, enc func(e *Encoder) error) []byte {
+	var b bytes.Buffer
+	if err := enc(NewEncoder(&b)); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	c := config.Default()
+	c.SpectThresh, c.Spect, c.FrameLen, c.Hop, c.BufSize = 60, 3, 64, 16, 1072
+	c.MelFilters, c.MelLowHz, c.MelHighHz, c.MFCCs, c.Lifter, c.Deltas = 20, 100, 4000, 13, 22, 2
+	c.DeriveTbins()
+	samples := make([]uint16, 2*MaxSamples+100)
+	for i := range samples {
+		samples[i] = uint16(i * 37)
+	}
+	spect := [][]uint16{{1, 2, 3}, {4, 5, 0xFFFF}}
+	pool := [][]int{{-1, 2}, {1 << 20, -(1 << 30)}}
+	d := match.Detection{Outcome: match.Match, Word: 1, Best: 1, Err: 95, Margin: 1206,
+		Confidence: 0.927, Errs: []int{1301, 95, -3}}
+	noise := adc.NewNoiseFloor(adc.DefaultNoiseParams())
+	seg := adc.Segment{Start: 75, End: 900, Reason: adc.VADEnd, Clicks: 2}
+
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	e.Params(c)
+	e.Samples(samples)
+	e.Spect(spect)
+	e.Pool(TypePool2, pool)
+	e.Noise(noise, seg, 0xBFFF)
+	e.Detection(d)
+	if err := e.End(); err != nil {
+		t.Fatal(err)
+	}
+
+	dec := NewDecoder(&b)
+	next := func(want Type) Frame {
+		f, err := dec.Next()
+		if err != nil || f.Type != want {
+			t.Fatalf("Next = %v, %v, want a %v frame", f.Type, err, want)
+		}
+		return f
+	}
+	if got, err := next(TypeParams).Config(); err != nil || got != c {
+		t.Errorf("Config = %+v, %v, want %+v", got, err, c)
+	}
+	var gotSamples []uint16
+	for k := 0; k < 3; k++ {
+		first, s, err := next(TypeSamples).Samples()
+		if err != nil || first != len(gotSamples) {
+			t.Fatalf("Samples frame %d: first %d, %v, want %d", k, first, err, len(gotSamples))
+		}
+		gotSamples = append(gotSamples, s...)
+	}
+	if !reflect.DeepEqual(gotSamples, samples) {
+		t.Errorf("Samples differ")
+	}
+	if got, err := next(TypeSpect).Spect(); err != nil || !reflect.DeepEqual(got, spect) {
+		t.Errorf("Spect = %v, %v, want %v", got, err, spect)
+	}
+	if got, err := next(TypePool2).Pool(); err != nil || !reflect.DeepEqual(got, pool) {
+		t.Errorf("Pool = %v, %v, want %v", got, err, pool)
+	}
+	start, stop := noise.Thresholds()
+	wantNoise := NoiseReport{Energy: noise.Energy, Rms: noise.Rms(), Frames: noise.Frames, Start: start,
+		Stop: stop, Peak: 0xBFFF, Seg: seg}
+	if got, err := next(TypeNoise).Noise(); err != nil || got != wantNoise {
+		t.Errorf("Noise = %+v, %v, want %+v", got, err, wantNoise)
+	}
+	if got, err := next(TypeDetection).Detection(); err != nil || !reflect.DeepEqual(got, d) {
+		t.Errorf("Detection = %+v, %v, want %+v", got, err, d)
+	}
+	next(TypeEnd)
+	if _, err := dec.Next(); err != io.EOF {
+		t.Errorf("Next after the last frame: %v, want io.EOF", err)
+	}
+	if dec.Skipped != 0 || dec.BadCrc != 0 {
+		t.Errorf("Skipped %d, BadCrc %d, want 0", dec.Skipped, dec.BadCrc)
+	}
+}
+
+func TestResync(t *testing.T) {
+	end := frameOf(t, (*Encoder).End)
+	spect := frameOf(t, func(e *Encoder) error { return e.Spect([][]uint16{{Sync0, Sync1}, {7, 8}}) })
+	corrupt := append([]byte(nil), spect...)
+	corrupt[len(corrupt)-3] ^= 0x10 // a payload bit
+	text := []byte("--noise-- floor 812\n\r")
+
+	tests := []struct {
+		name    string
+		stream  [][]byte
+		want    []Type
+		badCrc  int
+		skipped int
+	}{
+		{"text between frames", [][]byte{text, spect, text, end}, []Type{TypeSpect, TypeEnd}, 0, 2 * len(text)},
+		{"lone sync bytes", [][]byte{{Sync0, 0x00, Sync1, Sync0}, end}, []Type{TypeEnd}, 0, 4},
+		{"false sync, bad crc", [][]byte{{Sync0, Sync1, byte(TypeEnd), 1, 0, 0, 0, 0}, end}, []Type{TypeEnd}, 1, 8},
+		{"corrupt frame dropped", [][]byte{corrupt, spect, end}, []Type{TypeSpect, TypeEnd}, 1, len(corrupt)},
+		{"sync inside a corrupt payload", [][]byte{corrupt[:len(corrupt)-2], end}, []Type{TypeEnd}, 1,
+			len(corrupt) - 2},
+		{"frame cut short", [][]byte{end, spect[:len(spect)-1]}, []Type{TypeEnd}, 0, 0},
+	}
+	for _, tt := range tests {
+		dec := NewDecoder(bytes.NewReader(bytes.Join(tt.stream, nil)))
+		var got []Type
+		for {
+			f, err := dec.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			got = append(got, f.Type)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: frames %v, want %v", tt.name, got, tt.want)
+		}
+		if dec.BadCrc != tt.badCrc || dec.Skipped != tt.skipped {
+			t.Errorf("%s: BadCrc %d, Skipped %d, want %d, %d", tt.name, dec.BadCrc, dec.Skipped, tt.badCrc, tt.skipped)
+		}
+	}
+}
+
+func TestLegacyParams(t *testing.T) {
+	c := config.Default()
+	c.Tbins, c.SpectThresh = 32, 60
+	var b bytes.Buffer
+	for _, v := range ParamValues(c)[:LegacyParams] {
+		b.Write([]byte{byte(v), byte(v >> 8)})
+	}
+	if got, err := (Frame{Type: TypeParams, Payload: b.Bytes()}).Config(); err != nil || got != c {
+		t.Errorf("Config of a %d value payload = %+v, %v, want %+v", LegacyParams, got, err, c)
+	}
+	if len(ParamNames) != len(ParamValues(c)) {
+		t.Errorf("%d ParamNames, %d ParamValues", len(ParamNames), len(ParamValues(c)))
+	}
+}
+
+func TestPayloadCorrupt(t *testing.T) {
+	tests := []struct {
+		name string
+		err  func() error
+	}{
+		{"short params", func() error { _, err := Frame{Payload: []byte{1, 0, 2}}.Config(); return err }},
+		{"params length", func() error { _, err := Frame{Payload: make([]byte, 2*LegacyParams+2)}.Config(); return err }},
+		{"long params", func() error { _, err := Frame{Payload: make([]byte, 2*len(ParamNames)+2)}.Config(); return err }},
+		{"spect size", func() error { _, err := Frame{Payload: []byte{1, 0, 2, 0, 9, 0}}.Spect(); return err }},
+		{"pool size", func() error { _, err := Frame{Payload: []byte{1, 0, 1, 0, 9}}.Pool(); return err }},
+		{"detection errs", func() error {
+			_, err := Frame{Payload: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 1, 0, 0, 0}}.Detection()
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.err(); err != ErrCorrupt {
+			t.Errorf("%s: %v, want ErrCorrupt", tt.name, err)
+		}
+	}
+}
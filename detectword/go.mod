@@ -0,0 +1,7 @@
+module localhost/detectword
+
+go 1.17
+
+replace localhost/adc => ../adc
+
+require localhost/adc v0.0.0-00010101000000-000000000000
@@ -0,0 +1,129 @@
+// @file TinyGo/detectword/match/detect.go
+// @date 2026.10.18
+// @info typed detection result with confidence; replaces ReduceWordDetect's 1, 0, 3 return
+//       and hard coded lse-dse thresholds with Detection and DetectParams
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 DetectParams.Matcher selects SquareErr or DTWErr
+// @date 2026.10.18 DetectParamsOf(), DetectConfig(); params from config.Config
+// @date 2026.10.18 Decide(); a margin below NoiseMargin is Noise. A large margin is a clear
+//                  match, not the baseline lse-dse noise rule
+// @date 2026.10.18 DefaultDetectParams() NoiseMargin 400, of config.Default()
+// @date 2026.10.18 DefaultDetectParams() MinConfidence and MaxErr 0, disabled, as --prod--
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package match
+
+import "localhost/detectword/config"
+
+// Outcome is the typed result of a detection
+type Outcome int
+
+const (
+	Match     Outcome = iota // best word detected
+	Ambiguous                // best word and runner up too close to tell apart
+	NoMatch                  // best word too far from its reference, e.g. an unknown word
+	Noise                    // noise capture; no word
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Match:
+		return "match"
+	case Ambiguous:
+		return "ambiguous"
+	case NoMatch:
+		return "nomatch"
+	case Noise:
+		return "noise"
+	}
+	return "unknown"
+}
+
+// DetectParams are the Detect decision thresholds
+type DetectParams struct {
+	NoiseMargin   int     // margin below is Noise, no word standing out; 0 disables
+	MinConfidence float64 // Confidence below is Ambiguous; 0 disables
+	MaxErr        int     // best word error above is NoMatch; 0 disables
+	Matcher       Matcher // reduction comparison; the zero Matcher is SquareErr
+}
+
+// DefaultDetectParams returns --prod-- thresholds, of config.Default(); NoiseMargin 400 is the
+// edge of the baseline ReduceWordDetect's -400 < lse-dse < 400 band, and MinConfidence and
+// MaxErr are 0, disabled, as they were there. MaxErr 1600, an average 10 dB error per cell
+// of a 4x4 peak pool, and MinConfidence 0.05 are starting points to tune with dweval
+func DefaultDetectParams() DetectParams {
+	return DetectParamsOf(config.Default())
+}
+
+// DetectParamsOf returns the DetectParams of config 'c'
+func DetectParamsOf(c config.Config) DetectParams {
+	return DetectParams{NoiseMargin: c.NoiseMargin, MinConfidence: c.MinConfidence, MaxErr: c.MaxErr,
+		Matcher: MatcherOf(c)}
+}
+
+// Detection is the result of Detect
+type Detection struct {
+	Outcome    Outcome
+	Word       int     // index into refs of the best word; NoWord unless Outcome is Match
+	Label      string  // refs[Word].Label; "" unless Outcome is Match
+	Best       int     // index of the lowest error word regardless of Outcome; NoWord for noise
+	Err        int     // error to the best word, by DetectParams.Matcher
+	Margin     int     // runner up error minus Err
+	Confidence float64 // Margin relative to the runner up error, 0 (tie) to 1
+	Errs       []int   // error to each of refs
+}
+
+// Detect resolves U16Spect, and its CreateU16SpectFromU16 'bIsNoise', into one of 'refs'
+// and rates the decision against 'p'
+func Detect( U16Spect [][]uint16, bIsNoise bool, refs []RefWord, p DetectParams, Fbins, Tbins,
+	vBlocks, hBlocks, vBlocks2, hBlocks2 int ) (d Detection) {
+	if bIsNoise {
+		return Detection{Outcome: Noise, Word: NoWord, Best: NoWord}
+	}
+	errs := MatcherErrs( U16Spect, refs, p.Matcher, Fbins, Tbins, vBlocks, hBlocks, vBlocks2, hBlocks2 )
+	return Decide(errs, refs, p)
+} // end Detect
+
+// DetectConfig is Detect with the params and decision thresholds of 'c'
+func DetectConfig( U16Spect [][]uint16, bIsNoise bool, refs []RefWord, c config.Config ) (d Detection) {
+	return Detect( U16Spect, bIsNoise, refs, DetectParamsOf(c), c.Fbins, c.Tbins,
+		c.VBlocks, c.HBlocks, c.VBlocks2, c.HBlocks2 )
+}
+
+// Decide rates errors 'errs' against each of 'refs'. Checked in order: an error above
+// p.MaxErr is NoMatch, a margin below p.NoiseMargin is Noise, a confidence below
+// p.MinConfidence is Ambiguous; otherwise the best word is a Match. Noise and other sounds
+// score alike against every reference, so a small margin, not a large one, marks them; the
+// baseline two word ReduceWordDetect's lse-dse at or above deltaLseDseNoisePos rejected
+// the clearest matches.
+func Decide(errs []int, refs []RefWord, p DetectParams) (d Detection) {
+	d = Detection{Outcome: NoMatch, Word: NoWord, Best: NoWord, Errs: errs}
+	if len(errs) < 2 || len(errs) > MaxRefWords || len(errs) != len(refs) {
+		return d
+	}
+	best, second := bestTwo(errs)
+	d.Best = best
+	d.Err = errs[best]
+	d.Margin = errs[second] - errs[best]
+	if errs[second] > 0 {
+		d.Confidence = float64(d.Margin) / float64(errs[second])
+	}
+	switch {
+	case p.MaxErr > 0 && d.Err > p.MaxErr:
+		d.Outcome = NoMatch
+	case p.NoiseMargin > 0 && d.Margin < p.NoiseMargin:
+		d.Outcome = Noise
+	case d.Confidence < p.MinConfidence:
+		d.Outcome = Ambiguous
+	default:
+		d.Outcome = Match
+		d.Word = best
+		d.Label = refs[best].Label
+	}
+	return d
+} // end Decide
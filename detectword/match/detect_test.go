@@ -0,0 +1,100 @@
+// @file TinyGo/detectword/match/detect_test.go
+// @date 2026.10.18
+// @info Decide outcomes at the Match, Ambiguous, NoMatch and Noise boundaries
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package match
+
+import "testing"
+
+var testRefs = []RefWord{{Label: "on"}, {Label: "off"}, {Label: "dim"}}
+
+func TestDecide(t *testing.T) {
+	p := DetectParams{NoiseMargin: 400, MinConfidence: 0.05, MaxErr: 1600}
+	conf := p // errors large enough for a confidence below 0.05 with a margin of 400
+	conf.MaxErr = 0
+	noNoise := p
+	noNoise.NoiseMargin = 0
+	off := DetectParams{} // every threshold disabled
+	tests := []struct {
+		name  string
+		errs  []int
+		p     DetectParams
+		want  Outcome
+		label string
+	}{
+		{"match", []int{100, 600, 900}, p, Match, "on"},
+		{"match second word", []int{600, 100, 900}, p, Match, "off"},
+		{"margin at NoiseMargin", []int{100, 500, 900}, p, Match, "on"},
+		{"margin below NoiseMargin", []int{100, 499, 900}, p, Noise, ""},
+		{"Err at MaxErr", []int{1600, 2000, 2100}, p, Match, "on"},
+		{"Err above MaxErr", []int{1601, 2001, 2100}, p, NoMatch, ""},
+		{"NoMatch before Noise", []int{1700, 1710, 2500}, p, NoMatch, ""},
+		{"confidence below MinConfidence", []int{8000, 8400, 9900}, conf, Ambiguous, ""}, // 0.0476
+		{"confidence at MinConfidence", []int{8000, 8422, 9900}, conf, Match, "on"},      // 0.0501
+		{"Noise before Ambiguous", []int{1000, 1050, 1900}, p, Noise, ""},
+		{"tie", []int{200, 200, 900}, p, Noise, ""},
+		{"tie without NoiseMargin", []int{200, 200, 900}, noNoise, Ambiguous, ""},
+		{"exact zero errors", []int{0, 0, 900}, noNoise, Ambiguous, ""},
+		{"thresholds disabled", []int{5000, 9000, 9000}, off, Match, "on"},
+		{"one word", []int{100}, p, NoMatch, ""},
+		{"errs and refs differ", []int{100, 300}, p, NoMatch, ""},
+	}
+	for _, tt := range tests {
+		d := Decide(tt.errs, testRefs, tt.p)
+		if d.Outcome != tt.want || d.Label != tt.label {
+			t.Errorf("%s: Decide(%v) = %v %q, want %v %q", tt.name, tt.errs, d.Outcome, d.Label, tt.want, tt.label)
+		}
+		if (d.Outcome == Match) != (d.Word != NoWord) {
+			t.Errorf("%s: Word %d with outcome %v", tt.name, d.Word, d.Outcome)
+		}
+	}
+}
+
+func TestDecideFields(t *testing.T) {
+	p := DetectParams{NoiseMargin: 100, MinConfidence: 0.05, MaxErr: 1600}
+	d := Decide([]int{900, 300, 500}, testRefs, p)
+	want := Detection{Outcome: Match, Word: 1, Label: "off", Best: 1, Err: 300, Margin: 200, Confidence: 0.4}
+	if d.Outcome != want.Outcome || d.Word != want.Word || d.Label != want.Label || d.Best != want.Best ||
+		d.Err != want.Err || d.Margin != want.Margin || d.Confidence != want.Confidence || len(d.Errs) != 3 {
+		t.Errorf("Decide = %+v, want %+v", d, want)
+	}
+	// Best is kept when the outcome is not a Match
+	if d := Decide([]int{1000, 1020, 1900}, testRefs, p); d.Outcome != Noise || d.Best != 0 || d.Word != NoWord {
+		t.Errorf("noise Decide = %v, Best %d, Word %d, want noise, 0, NoWord", d.Outcome, d.Best, d.Word)
+	}
+}
+
+func TestDecideDefault(t *testing.T) {
+	p := DefaultDetectParams()
+	if p.NoiseMargin != 400 {
+		t.Fatalf("default NoiseMargin %d, want 400, the baseline lse-dse band edge", p.NoiseMargin)
+	}
+	tests := []struct {
+		errs []int
+		want Outcome
+	}{
+		{[]int{100, 499, 900}, Noise},  // margin 399
+		{[]int{100, 500, 900}, Match},  // margin 400
+		{[]int{0, 0, 900}, Noise},      // tie
+		{[]int{900, 500, 100}, Match},  // margin 400 to the runner up
+		{[]int{200, 599, 1200}, Noise}, // margin 399
+	}
+	for _, tt := range tests {
+		if d := Decide(tt.errs, testRefs, p); d.Outcome != tt.want {
+			t.Errorf("default Decide(%v) = %v, want %v", tt.errs, d.Outcome, tt.want)
+		}
+	}
+}
+
+func TestDetectNoise(t *testing.T) {
+	d := Detect(nil, true, testRefs, DefaultDetectParams(), 64, 64, 8, 8, 4, 4)
+	if d.Outcome != Noise || d.Word != NoWord || d.Best != NoWord {
+		t.Errorf("Detect of a noise capture = %+v, want Noise", d)
+	}
+}
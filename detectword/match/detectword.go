@@ -0,0 +1,221 @@
+// @file TinyGo/sandbox_dw/detectword.go
+// @date 2022.03.08
+// @info detectword_pico specific functions
+
+// Copyright 2022 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2022.03.15 added --warning-- CreateU16Spect clipping float %v to uint16 0
+// @date 2022.03.15 copied and updated from sandbox_dw/detectword.go and commented --no pico-- 'exec' use
+// @date 2022.03.16 added ReduceDetectWordCreateRef(); moved Ref processing out of loops
+// @date 2022.03.25 changed ReduceWordDetect deltaLseDse from 200 to 0; better for live captured references;
+//                  added 'deltaLseDseNoiseNeg/Pos' to detection scheme
+// @date 2022.03.27 CreateU16SpectFromU16FromU16(); removed hex processing
+// @date 2022.03.28 FftLogShift(); combines 20*math.Log10() and fft shift
+// @date 2022.04.01 added NormalizeU16_ac_threshold() calls for sound level detection
+// @date 2022.04.08 code cleanup; added bIsNoise as Create*FromU*() return
+// @date 2022.04.09 tuning: deltaLseDseNoiseNeg/deltaLseDseNoisePos from -250/250 to -400/400
+// @date 2022.04.13 commented all Print* for --prod-- mode; see --quiet--
+// @date 2026.10.18 moved to package match; spectrogram creation moved to dsp/spect.go
+// @date 2026.10.18 added ReduceWordDetectErr(); returns lse and dse with the decision
+// @date 2026.10.18 added ReduceWordDetectN(), RefWord; N-way detection over up to MaxRefWords words
+// @date 2026.10.18 ReduceWordDetectCreateRef pools Tbins rows by Fbins cols, was Fbins rows by Tbins cols;
+//                  the same for Tbins == Fbins, no longer misshapen, or a divide by zero, when they differ
+// @date 2026.10.18 ReduceWordDetectErr() reduces by ReduceWordDetectCreateRef, once for light and dark
+// @date 2026.10.18 RefWord.Takes; RefWordErrs() uses the nearest take when a word keeps its takes
+// @date 2026.10.18 Matcher selects SquareErr or DTWErr; ReduceWordDetectWith(), MatcherErrs()
+// @date 2026.10.18 deltaLseDse thresholds from config.Config; ReduceWordDetectConfig(), ReduceConfig()
+// @date 2026.10.18 ReduceWordDetect(), ReduceWordDetectN() and DecideN() decide by Decide with DetectParams,
+//                  in place of config.Default() thresholds; ReduceWordDetectN(), DecideN() return the label;
+//                  removed ReduceWordDetectWith() and ReduceWordDetectConfig(), ReduceWordDetectErr() with
+//                  DetectParamsOf() is both
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package match
+
+import (
+	"localhost/detectword/config"
+	"localhost/detectword/dsp"
+)
+
+// MaxRefWords is the largest vocabulary ReduceWordDetectN accepts
+const MaxRefWords = 8
+
+// NoWord is the ReduceWordDetectN word index when no reference word is detected;
+// the N-way equivalent of ReduceWordDetect returning '3'
+const NoWord = -1
+
+// RefWord is a labelled reference word, e.g. "on", reduced by ReduceWordDetectCreateRef
+type RefWord struct {
+	Label   string
+	Reduced [][]int // final (peak pool) reduction
+	Takes   [][][]int // optional enrolled takes, each a final reduction; matched nearest neighbour
+}
+
+
+// ReduceWordDetect resolves U16SpectRef and U16Spect into word 'Light' or 'Dark'
+// U16SpectRef have been reduced to 'iSpectReducedLight/Dark' before call
+// --prod-- tuned with SpectThresh=50, vBlocks=8, hBlocksk=8 (avg), vBlocks2=4, hBlocks2=4 (peak),
+// Fbins=64, Tbins=64, buf_size=1024, Tsamp=166us; decided by Decide with 'p'
+func ReduceWordDetect(
+	U16Spect [][]uint16, iSpectRefReducedLight, iSpectRefReducedDark [][]int, p DetectParams, SpectThresh uint16,
+	buf_size, Fbins, Tbins, vBlocks, hBlocks, vBlocks2, hBlocks2 int ) (isLight int ) {
+	isLight, _, _ = ReduceWordDetectErr( U16Spect, iSpectRefReducedLight, iSpectRefReducedDark, p, SpectThresh,
+		buf_size, Fbins, Tbins, vBlocks, hBlocks, vBlocks2, hBlocks2 )
+	return isLight
+}
+
+// ReduceWordDetectErr is ReduceWordDetect, also returning the light and dark errors 'lse'
+// and 'dse', by p.Matcher, the decision is based on
+func ReduceWordDetectErr(
+	U16Spect [][]uint16, iSpectRefReducedLight, iSpectRefReducedDark [][]int, p DetectParams, SpectThresh uint16,
+	buf_size, Fbins, Tbins, vBlocks, hBlocks, vBlocks2, hBlocks2 int ) (isLight, lse, dse int ) {
+	// one reduction, as the references were built, compared to both
+	iSpectReduced, _ := ReduceWordDetectCreateRef( U16Spect, Fbins, Tbins, vBlocks, hBlocks, vBlocks2, hBlocks2 )
+	lse = p.Matcher.Err( iSpectRefReducedLight, iSpectReduced ) // light err
+	dse = p.Matcher.Err( iSpectRefReducedDark, iSpectReduced ) // dark err
+	// --quiet-- fmt.Println("LSE:", lse, "DSE:", dse, "del", lse-dse, "\n\r" )
+	return lightDark( lse, dse, p ), lse, dse
+} // end ReduceWordDetectErr
+
+// lightDark is the ReduceWordDetect decision on light and dark errors 'lse', 'dse': 1 for
+// 'Light', 0 for 'Dark', 3 for noise, Decide's Noise, NoMatch and Ambiguous alike
+func lightDark( lse, dse int, p DetectParams ) (isLight int) {
+	d := Decide( []int{lse, dse}, lightDarkRefs, p )
+	if d.Outcome != Match {
+		return 3 // set to 'noise detected'
+	}
+	return 1 - d.Word // index 0 'Light', 1 'Dark'
+}
+
+var lightDarkRefs = []RefWord{{Label: "Light"}, {Label: "Dark"}}
+
+// ReduceConfig is ReduceWordDetectCreateRef with the Fbins, Tbins and block sizes of 'c'
+func ReduceConfig( U16Spect [][]uint16, c config.Config ) (iSpectReduced, iSpectReducedPoolAvg [][]int) {
+	return ReduceWordDetectCreateRef( U16Spect, c.Fbins, c.Tbins, c.VBlocks, c.HBlocks, c.VBlocks2, c.HBlocks2 )
+}
+
+// ReduceWordDetectCreateRef provides a separate reduction function for reference words and
+// returns both the final reduction, and the intermediate pool1 state for diagnostics
+func ReduceWordDetectCreateRef( U16SpectRef [][]uint16, Fbins, Tbins int,
+	vBlocks, hBlocks, vBlocks2, hBlocks2 int ) (i16SpectRefReduced, i16SpectRefReducedPoolAvg [][]int  ) {
+	rows0 := Tbins; cols0 := Fbins    // org array size, time rows by frequency cols // --dev-- --was-- Fbins/2
+	rows1 := rows0/vBlocks; cols1 := cols0/hBlocks // working subslice size
+
+	// first reduction by average value
+	// --dev-- for Fbins/2: iSpectRefReduced := dsp.ReduceUint16ToIntArrayAvg( U16SpectRef[rows0:][:], rows1, cols1 )
+	iSpectRefReduced := dsp.ReduceUint16ToIntArrayAvg( U16SpectRef[:][:], rows1, cols1 )
+	// --dev-- iSpectRefReduced := ReduceUint16ToIntArrayPeak( U16SpectRef[rows0:][:], rows1, cols1 )
+	// fmt.Println("--d-- iSpectRefReduced", iSpectRefReduced )
+
+	// create intermediate copy for return diags
+	lenIspectRef := len(iSpectRefReduced)
+	lenIspectRef0 := len(iSpectRefReduced[0])
+	iSpectRefReducedPoolAvg := make([][]int, lenIspectRef)
+	for i,_ := range iSpectRefReducedPoolAvg { // allocate new memory
+		iSpectRefReducedPoolAvg[i] = make ([]int, len(iSpectRefReduced[0]))
+	}
+	for i:=0; i<lenIspectRef; i++ { // perform copy 
+		for j:=0; j<lenIspectRef0; j++ {
+			iSpectRefReducedPoolAvg[i][j] = iSpectRefReduced[i][j]
+		}
+	}
+
+	// second reduction by peak value
+	rows0 = len(iSpectRefReduced); cols0 = len(iSpectRefReduced[0])    // org array size
+	rows1 = rows0/vBlocks2; cols1 = cols0/hBlocks2 // working subslice size
+ 	// fmt.Printf("--d-- rows0: %d, cols0: %d\n\r", rows0, cols0)
+	// fmt.Printf("--d-- rows1: %d, cols1: %d\n\r", rows1, cols1)		
+	// --dev-- iSpectRefReduced = ReduceIntArrayAvg( iSpectRefReduced, rows1, cols1 )
+	iSpectRefReduced = dsp.ReduceIntArrayPeak( iSpectRefReduced, rows1, cols1 )
+	// fmt.Println("--d-- iSpectRefReduced", iSpectRefReduced )
+
+	return iSpectRefReduced, iSpectRefReducedPoolAvg
+} // end ReduceWordDetectCreateRef
+
+// ReduceWordDetectN resolves U16Spect into one of 'refs', 2 to MaxRefWords reference words,
+// by DecideN with 'p'. Returns the label and index of the best matching word, its error
+// 'wordErr', by p.Matcher, and the error 'margin' over the runner up; 'label' is "" and
+// 'iWord' NoWord when not detected.
+func ReduceWordDetectN( U16Spect [][]uint16, refs []RefWord, p DetectParams, SpectThresh uint16, buf_size, Fbins, Tbins,
+	vBlocks, hBlocks, vBlocks2, hBlocks2 int ) (label string, iWord, wordErr, margin int) {
+	errs := MatcherErrs( U16Spect, refs, p.Matcher, Fbins, Tbins, vBlocks, hBlocks, vBlocks2, hBlocks2 )
+	return DecideN(errs, refs, p)
+} // end ReduceWordDetectN
+
+// RefWordErrs reduces U16Spect as ReduceWordDetectCreateRef does for references, and
+// returns its square error to each of 'refs'; the error to the nearest take for refs with Takes
+func RefWordErrs( U16Spect [][]uint16, refs []RefWord, Fbins, Tbins,
+	vBlocks, hBlocks, vBlocks2, hBlocks2 int ) (errs []int) {
+	return MatcherErrs( U16Spect, refs, Matcher{}, Fbins, Tbins, vBlocks, hBlocks, vBlocks2, hBlocks2 )
+}
+
+// MatcherErrs is RefWordErrs comparing by 'mt'
+func MatcherErrs( U16Spect [][]uint16, refs []RefWord, mt Matcher, Fbins, Tbins,
+	vBlocks, hBlocks, vBlocks2, hBlocks2 int ) (errs []int) {
+	iSpectReduced, _ := ReduceWordDetectCreateRef( U16Spect, Fbins, Tbins, vBlocks, hBlocks, vBlocks2, hBlocks2 )
+	errs = make([]int, len(refs))
+	for i,_ := range refs {
+		errs[i] = mt.RefErr( refs[i], iSpectReduced )
+	}
+	return errs
+}
+
+// RefWordErr returns the square error of reduction 'iSpectReduced' to 'ref'; the error to
+// the nearest of ref.Takes when kept, else to ref.Reduced
+func RefWordErr( ref RefWord, iSpectReduced [][]int ) (sqErr int) {
+	return Matcher{}.RefErr( ref, iSpectReduced )
+}
+
+// RefErr is RefWordErr comparing by 'm'
+func (m Matcher) RefErr( ref RefWord, iSpectReduced [][]int ) (err int) {
+	if len(ref.Takes) == 0 {
+		return m.Err( ref.Reduced, iSpectReduced )
+	}
+	err = m.Err( ref.Takes[0], iSpectReduced )
+	for _, take := range ref.Takes[1:] {
+		if e := m.Err( take, iSpectReduced ); e < err {
+			err = e
+		}
+	}
+	return err
+}
+
+// DecideN is Decide of errors 'errs' to 'refs' with 'p', returning only the detected word;
+// 'label' is "" and 'iWord' NoWord unless the outcome is Match. 'wordErr' and 'margin' are
+// of the best word regardless, 0 when 'errs' cannot be decided.
+func DecideN(errs []int, refs []RefWord, p DetectParams) (label string, iWord, wordErr, margin int) {
+	d := Decide(errs, refs, p)
+	return d.Label, d.Word, d.Err, d.Margin
+} // end DecideN
+
+// bestTwo returns the indices of the lowest and second lowest of 'errs', len(errs) >= 2;
+// ties go to the lower index
+func bestTwo(errs []int) (best, second int) {
+	best, second = 0, 1
+	if errs[1] < errs[0] {
+		best, second = 1, 0
+	}
+	for i:=2; i<len(errs); i++ {
+		if errs[i] < errs[best] {
+			best, second = i, best
+		} else if errs[i] < errs[second] {
+			second = i
+		}
+	}
+	return best, second
+}
+
+// SquareErr returns the sum square error of 'iTarget' against reference 'iRef'; 'iTarget' is
+// at least the size of 'iRef'
+func SquareErr( iRef, iTarget [][]int ) (sqErr int) {
+	for i,_ := range iRef {
+		for j,_ := range iRef[0] {
+			d := iRef[i][j] - iTarget[i][j]
+			sqErr += d*d
+		}
+	}
+	return sqErr
+}
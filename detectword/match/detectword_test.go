@@ -0,0 +1,171 @@
+// @file TinyGo/detectword/match/detectword_test.go
+// @date 2026.10.18
+// @info ReduceWordDetectCreateRef pool shapes and orientation, and the light/dark and N-way
+//       decisions on their errors
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+// blockSpect returns a Tbins x Fbins spectrogram whose value is the index of its 'vBlocks' x
+// 'hBlocks' avg pool block, row major; the pool averages are then exact block indices
+func blockSpect(tbins, fbins, vBlocks, hBlocks int) [][]uint16 {
+	s := make([][]uint16, tbins)
+	for t := range s {
+		s[t] = make([]uint16, fbins)
+		for f := range s[t] {
+			s[t][f] = uint16(t/(tbins/vBlocks)*hBlocks + f/(fbins/hBlocks))
+		}
+	}
+	return s
+}
+
+// blockIndices returns the rows x cols matrix of values i*step + j*jstep + off
+func blockIndices(rows, cols, step, jstep, off int) [][]int {
+	m := make([][]int, rows)
+	for i := range m {
+		m[i] = make([]int, cols)
+		for j := range m[i] {
+			m[i][j] = i*step + j*jstep + off
+		}
+	}
+	return m
+}
+
+func TestReduceWordDetectCreateRefShape(t *testing.T) {
+	tests := []struct {
+		name                                 string
+		tbins, fbins                         int
+		vBlocks, hBlocks, vBlocks2, hBlocks2 int
+	}{
+		{"square --prod--", 64, 64, 8, 8, 4, 4},
+		{"short time", 32, 64, 8, 8, 4, 4},
+		{"long time", 128, 32, 8, 8, 4, 4},
+		{"non square blocks", 64, 32, 16, 4, 4, 2},
+	}
+	for _, tt := range tests {
+		s := blockSpect(tt.tbins, tt.fbins, tt.vBlocks, tt.hBlocks)
+		reduced, poolAvg := ReduceWordDetectCreateRef(s, tt.fbins, tt.tbins,
+			tt.vBlocks, tt.hBlocks, tt.vBlocks2, tt.hBlocks2)
+		// avg pool: VBlocks rows over time, HBlocks cols over frequency, each its block index
+		if want := blockIndices(tt.vBlocks, tt.hBlocks, tt.hBlocks, 1, 0); !reflect.DeepEqual(poolAvg, want) {
+			t.Errorf("%s: avg pool\n got %v\nwant %v", tt.name, poolAvg, want)
+		}
+		// peak pool: VBlocks2 x HBlocks2, each the last, largest, index of its avg pool block
+		dv, dh := tt.vBlocks/tt.vBlocks2, tt.hBlocks/tt.hBlocks2
+		want := blockIndices(tt.vBlocks2, tt.hBlocks2, dv*tt.hBlocks, dh, (dv-1)*tt.hBlocks+dh-1)
+		if !reflect.DeepEqual(reduced, want) {
+			t.Errorf("%s: peak pool\n got %v\nwant %v", tt.name, reduced, want)
+		}
+	}
+}
+
+func TestReduceWordDetectErr(t *testing.T) {
+	const tbins, fbins = 32, 64
+	word := blockSpect(tbins, fbins, 8, 8)
+	ref, _ := ReduceWordDetectCreateRef(word, fbins, tbins, 8, 8, 4, 4)
+	off := func(d int) [][]int { // ref off by 'd' in one block; sq err d*d
+		r := make([][]int, len(ref))
+		for i := range ref {
+			r[i] = append([]int(nil), ref[i]...)
+		}
+		r[1][1] += d
+		return r
+	}
+	p := DetectParams{NoiseMargin: 50, MinConfidence: 0.05, MaxErr: 800}
+
+	tests := []struct {
+		name        string
+		light, dark [][]int
+		want        int
+		lse, dse    int
+	}{
+		{"light", ref, off(10), 1, 0, 100},
+		{"dark", off(10), ref, 0, 100, 0},
+		{"tie is noise", ref, ref, 3, 0, 0},
+		{"margin below NoiseMargin", ref, off(7), 3, 0, 49},
+		{"light above MaxErr", off(30), off(40), 3, 900, 1600},
+	}
+	for _, tt := range tests {
+		isLight, lse, dse := ReduceWordDetectErr(word, tt.light, tt.dark, p, 50, 1024, fbins, tbins, 8, 8, 4, 4)
+		if isLight != tt.want || lse != tt.lse || dse != tt.dse {
+			t.Errorf("%s: ReduceWordDetectErr = %d, %d, %d, want %d, %d, %d",
+				tt.name, isLight, lse, dse, tt.want, tt.lse, tt.dse)
+		}
+		if got := ReduceWordDetect(word, tt.light, tt.dark, p, 50, 1024, fbins, tbins, 8, 8, 4, 4); got != tt.want {
+			t.Errorf("%s: ReduceWordDetect = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// the errors are p.Matcher's; a DTW band of 1 is SquareErr for these unshifted rows
+	dtw := p
+	dtw.Matcher = Matcher{Metric: DTWMetric, Band: 1}
+	if isLight, lse, dse := ReduceWordDetectErr(word, ref, off(10), dtw, 50, 1024, fbins, tbins, 8, 8, 4, 4); isLight != 1 || lse != 0 || dse != 100 {
+		t.Errorf("DTW ReduceWordDetectErr = %d, %d, %d, want 1, 0, 100", isLight, lse, dse)
+	}
+}
+
+func TestDecideN(t *testing.T) {
+	p := DetectParams{NoiseMargin: 100, MinConfidence: 0.05, MaxErr: 1000}
+	tests := []struct {
+		errs                   []int
+		label                  string
+		iWord, wordErr, margin int
+	}{
+		{[]int{100, 300, 900}, "on", 0, 100, 200},
+		{[]int{300, 100, 900}, "off", 1, 100, 200},
+		{[]int{500, 900, 200}, "dim", 2, 200, 300},
+		{[]int{100, 100, 900}, "", NoWord, 100, 0}, // tie; margin below NoiseMargin
+		{[]int{100, 199, 900}, "", NoWord, 100, 99},
+		{[]int{100, 200, 900}, "on", 0, 100, 100},
+		{[]int{1001, 1500, 1900}, "", NoWord, 1001, 499}, // above MaxErr
+		{[]int{100}, "", NoWord, 0, 0},
+		{[]int{100, 300}, "", NoWord, 0, 0}, // errs and refs differ
+	}
+	for _, tt := range tests {
+		label, iWord, wordErr, margin := DecideN(tt.errs, testRefs, p)
+		if label != tt.label || iWord != tt.iWord || wordErr != tt.wordErr || margin != tt.margin {
+			t.Errorf("DecideN(%v) = %q, %d, %d, %d, want %q, %d, %d, %d",
+				tt.errs, label, iWord, wordErr, margin, tt.label, tt.iWord, tt.wordErr, tt.margin)
+		}
+	}
+}
+
+func TestReduceWordDetectN(t *testing.T) {
+	const tbins, fbins = 64, 64
+	// words 3 apart in every pool cell: errors 0, 144 and 576 apart
+	var spects [][][]uint16
+	refs := make([]RefWord, 3)
+	for i := range refs {
+		s := blockSpect(tbins, fbins, 8, 8)
+		for t := range s {
+			for f := range s[t] {
+				s[t][f] += uint16(3 * i)
+			}
+		}
+		spects = append(spects, s)
+		refs[i].Label = []string{"on", "off", "dim"}[i]
+		refs[i].Reduced, _ = ReduceWordDetectCreateRef(s, fbins, tbins, 8, 8, 4, 4)
+	}
+	p := DetectParams{NoiseMargin: 100, MinConfidence: 0.05}
+	for i, s := range spects {
+		label, iWord, wordErr, margin := ReduceWordDetectN(s, refs, p, 50, 1024, fbins, tbins, 8, 8, 4, 4)
+		if label != refs[i].Label || iWord != i || wordErr != 0 || margin != 144 {
+			t.Errorf("%s: ReduceWordDetectN = %q, %d, err %d, margin %d, want %q, %d, err 0, margin 144",
+				refs[i].Label, label, iWord, wordErr, margin, refs[i].Label, i)
+		}
+	}
+	p.NoiseMargin = 145 // the margin of 144 no longer stands out
+	if label, iWord, _, _ := ReduceWordDetectN(spects[0], refs, p, 50, 1024, fbins, tbins, 8, 8, 4, 4); label != "" || iWord != NoWord {
+		t.Errorf("ReduceWordDetectN with NoiseMargin 145 = %q, %d, want \"\", NoWord", label, iWord)
+	}
+}
@@ -0,0 +1,128 @@
+// @file TinyGo/detectword/match/dtw.go
+// @date 2026.10.18
+// @info dynamic time warping comparison of reductions; tolerates words spoken slower or
+//       faster than their reference, which SquareErr scores cell by cell
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 MatcherOf() config.Config
+// @date 2026.10.18 DTWErr() clears only the band edges of each row, O(rows * band) as documented
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package match
+
+import "localhost/detectword/config"
+
+// Metric selects how a reduction is compared to a reference
+type Metric int
+
+const (
+	SquareMetric Metric = iota // SquareErr, cell by cell; ReduceWordDetect's comparison
+	DTWMetric                  // DTWErr, time rows aligned within Matcher.Band
+)
+
+func (m Metric) String() string {
+	switch m {
+	case SquareMetric:
+		return "sq"
+	case DTWMetric:
+		return "dtw"
+	}
+	return "unknown"
+}
+
+// Matcher compares reductions by Metric; the zero Matcher is SquareErr
+type Matcher struct {
+	Metric Metric
+	Band   int // DTWMetric Sakoe-Chiba band, in reduced time rows; 0 is SquareErr
+}
+
+// MatcherOf returns the Matcher of config 'c'
+func MatcherOf( c config.Config ) Matcher {
+	return Matcher{ Metric: Metric(c.Metric), Band: c.DTWBand }
+}
+
+// Err returns the error of 'iTarget' against reference 'iRef'
+func (m Matcher) Err( iRef, iTarget [][]int ) int {
+	if m.Metric == DTWMetric {
+		return DTWErr( iRef, iTarget, m.Band )
+	}
+	return SquareErr( iRef, iTarget )
+}
+
+// DTWErr returns the dynamic time warping distance of 'iTarget' against reference 'iRef'.
+// Rows are time, as the first index of U16Spect, and the distance of a row pair is the
+// sum square error of their columns. The warping path stays within 'band' rows of the
+// diagonal (Sakoe-Chiba); band 0 is the diagonal only, and equals SquareErr. The distance
+// is the unnormalized path sum, so it is never above SquareErr and DetectParams thresholds
+// tuned for SquareErr remain conservative. Uses two rows of state, O(rows * band) time.
+func DTWErr( iRef, iTarget [][]int, band int ) int {
+	n := len(iRef); m := len(iTarget)
+	if n == 0 || m == 0 {
+		return 0
+	}
+	if d := n - m; band < d || band < -d { // the end point must lie within the band
+		if d < 0 {
+			d = -d
+		}
+		band = d
+	}
+	const inf = int(^uint(0) >> 1)
+	prev := make([]int, m)
+	cur := make([]int, m)
+	for i:=0; i<n; i++ {
+		lo := i - band; hi := i + band
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > m-1 {
+			hi = m - 1
+		}
+		// cells outside the band are unreachable; only the edge cells next to it are read,
+		// cur[lo-1] by this row and cur[hi+1] as prev by the next
+		if lo > 0 {
+			cur[lo-1] = inf
+		}
+		if hi < m-1 {
+			cur[hi+1] = inf
+		}
+		for j:=lo; j<=hi; j++ {
+			best := inf // cheapest predecessor: (i-1,j-1), (i-1,j), (i,j-1)
+			switch {
+			case i == 0 && j == 0:
+				best = 0
+			case i == 0:
+				best = cur[j-1]
+			case j == 0:
+				best = prev[j]
+			default:
+				best = prev[j-1]
+				if prev[j] < best {
+					best = prev[j]
+				}
+				if cur[j-1] < best {
+					best = cur[j-1]
+				}
+			}
+			if best == inf {
+				cur[j] = inf
+				continue
+			}
+			cur[j] = best + rowSquareErr( iRef[i], iTarget[j] )
+		}
+		prev, cur = cur, prev
+	}
+	return prev[m-1]
+} // end DTWErr
+
+// rowSquareErr returns the sum square error of row 'b' against reference row 'a'
+func rowSquareErr( a, b []int ) (sqErr int) {
+	for k,_ := range a {
+		d := a[k] - b[k]
+		sqErr += d*d
+	}
+	return sqErr
+}
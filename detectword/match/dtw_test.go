@@ -0,0 +1,119 @@
+// @file TinyGo/detectword/match/dtw_test.go
+// @date 2026.10.18
+// @info DTWErr on identical, time shifted and unequal length reductions, and its bound by
+//       SquareErr
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package match
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// rows returns a reduction of one 2 col row per value, v and 2v
+func rows(vals ...int) [][]int {
+	m := make([][]int, len(vals))
+	for i, v := range vals {
+		m[i] = []int{v, 2 * v}
+	}
+	return m
+}
+
+func TestDTWErrIdentical(t *testing.T) {
+	ref := rows(10, 20, 40, 30, 5, 0, 15, 25)
+	for band := 0; band <= len(ref); band++ {
+		if got := DTWErr(ref, rows(10, 20, 40, 30, 5, 0, 15, 25), band); got != 0 {
+			t.Errorf("band %d: identical DTWErr = %d, want 0", band, got)
+		}
+	}
+}
+
+func TestDTWErrShift(t *testing.T) {
+	ref := rows(0, 0, 10, 20, 30, 40, 50, 50)
+	shifted := rows(0, 10, 20, 30, 40, 50, 50, 50) // the word one row early, held at each end
+	sq := SquareErr(ref, shifted)
+	if sq == 0 {
+		t.Fatal("shifted rows have no square error")
+	}
+	tests := []struct {
+		band int
+		want int
+	}{
+		{0, sq}, // the diagonal only
+		{1, 0},  // the one row shift is within the band
+		{2, 0},
+	}
+	for _, tt := range tests {
+		if got := DTWErr(ref, shifted, tt.band); got != tt.want {
+			t.Errorf("band %d: shifted DTWErr = %d, want %d", tt.band, got, tt.want)
+		}
+	}
+	// a two row shift needs band 2
+	ref2 := rows(0, 0, 0, 10, 20, 30, 40, 40)
+	early := rows(0, 10, 20, 30, 40, 40, 40, 40)
+	if DTWErr(ref2, early, 1) == 0 || DTWErr(ref2, early, 2) != 0 {
+		t.Errorf("two row shift: band 1 %d, band 2 %d, want > 0, 0", DTWErr(ref2, early, 1), DTWErr(ref2, early, 2))
+	}
+}
+
+func TestDTWErrLengths(t *testing.T) {
+	long := rows(0, 10, 20, 30, 40, 50, 60, 70)
+	short := rows(0, 20, 40, 70)
+	// a band narrower than the length difference is widened to it, rather than indexing
+	// out of range or leaving the end point unreachable
+	want := DTWErr(long, short, 4)
+	for band := 0; band < 4; band++ {
+		if got := DTWErr(long, short, band); got != want {
+			t.Errorf("band %d: long vs short DTWErr = %d, want the band 4 %d", band, got, want)
+		}
+		if got := DTWErr(short, long, band); got != DTWErr(short, long, 4) {
+			t.Errorf("band %d: short vs long DTWErr = %d, want the band 4 %d", band, got, DTWErr(short, long, 4))
+		}
+	}
+	if got := DTWErr(nil, short, 1); got != 0 {
+		t.Errorf("empty reference DTWErr = %d, want 0", got)
+	}
+}
+
+func TestDTWErrBound(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func() [][]int {
+		m := make([][]int, 4)
+		for i := range m {
+			m[i] = []int{r.Intn(100), r.Intn(100), r.Intn(100), r.Intn(100)}
+		}
+		return m
+	}
+	for k := 0; k < 100; k++ {
+		a, b := random(), random()
+		sq := SquareErr(a, b)
+		if got := DTWErr(a, b, 0); got != sq {
+			t.Fatalf("band 0 DTWErr = %d, want SquareErr %d", got, sq)
+		}
+		prev := sq
+		for band := 1; band < 4; band++ { // wider bands only find cheaper paths
+			got := DTWErr(a, b, band)
+			if got > prev {
+				t.Fatalf("band %d DTWErr = %d, above band %d's %d", band, got, band-1, prev)
+			}
+			prev = got
+		}
+	}
+}
+
+func TestMatcherErr(t *testing.T) {
+	ref := rows(0, 0, 10, 20, 30, 40, 50, 50)
+	shifted := rows(0, 10, 20, 30, 40, 50, 50, 50)
+	if got, want := (Matcher{}).Err(ref, shifted), SquareErr(ref, shifted); got != want {
+		t.Errorf("zero Matcher Err = %d, want SquareErr %d", got, want)
+	}
+	if got := (Matcher{Metric: DTWMetric, Band: 1}).Err(ref, shifted); got != 0 {
+		t.Errorf("DTW Matcher Err = %d, want 0", got)
+	}
+}
@@ -0,0 +1,131 @@
+// @file TinyGo/detectword/match/enroll.go
+// @date 2026.10.18
+// @info multi-take reference word enrollment; takes disagreeing with the others are
+//       rejected, the rest averaged into one template or kept for nearest neighbour matching
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 EnrollTakeNoise(); noise threshold parameter, e.g. from adc.NoiseFloor
+// @date 2026.10.18 EnrollParamsOf(), EnrollTakeConfig(); params from config.Config
+// @date 2026.10.18 EnrollTakeConfig() spectrogram by dsp.CreateU16SpectConfig, overlapping frames included
+// @date 2026.10.18 empty takes are noise, not an index panic in NormalizeU16_ac_threshold
+// @date 2026.10.18 EnrollTake() takes EnrollParams, its Cfg the spectrogram and reduction params;
+//                  replaces EnrollTakeNoise() and EnrollTakeConfig()
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+package match
+
+import (
+	"errors"
+	"sort"
+
+	"localhost/detectword/config"
+	"localhost/detectword/dsp"
+)
+
+// ErrEnroll is returned by EnrollWord when too few takes agree; re-enroll the word
+var ErrEnroll = errors.New("enroll: takes disagree")
+
+// EnrollParams control EnrollTake and EnrollWord
+type EnrollParams struct {
+	Takes        int     // takes per word, K
+	RejectFactor float64 // reject a take whose mean error to the others exceeds RejectFactor x the median mean
+	RejectFloor  int     // ... and exceeds RejectFloor; keeps near identical takes from rejecting each other
+	MinAccepted  int     // fewest takes accepted for a template; fewer is ErrEnroll
+	KeepTakes    bool    // keep all accepted takes for nearest neighbour matching, else average them
+	Cfg          config.Config // spectrogram and reduction params of EnrollTake
+}
+
+// DefaultEnrollParams returns --prod-- enrollment, of config.Default()
+func DefaultEnrollParams() EnrollParams {
+	return EnrollParamsOf(config.Default())
+}
+
+// EnrollParamsOf returns the EnrollParams of config 'c'
+func EnrollParamsOf( c config.Config ) EnrollParams {
+	return EnrollParams{Takes: c.Takes, RejectFactor: c.RejectFactor, RejectFloor: c.RejectFloor,
+		MinAccepted: c.MinAccepted, KeepTakes: c.KeepTakes, Cfg: c}
+}
+
+// EnrollTake reduces one captured take 'uBuf' as the firmware does a reference word, with the
+// spectrogram and reduction params of p.Cfg, its FrameLen and Hop included; 'HammingFftPoints'
+// is p.Cfg.FftPoints() long. 'bIsNoise' is true, and the take unusable, when 'uBuf' is empty
+// or NormalizeU16_ac_threshold or CreateU16SpectConfig find noise by 'noiseThresh', e.g.
+// dsp.NoiseThreshold 0xBFFF or adc.NoiseFloor.PeakThreshold().
+func EnrollTake( uBuf []uint16, HammingFftPoints []float64, noiseThresh uint16, p EnrollParams ) (
+	iSpectRefReduced, iSpectRefReducedPoolAvg [][]int, U16SpectRef [][]uint16, bIsNoise bool) {
+	if len(uBuf) == 0 { // nothing captured
+		return nil, nil, nil, true
+	}
+	// verify time domain uBuf is not noise; dsp.NoiseThreshold 0xBFFF is 0.75 0xFFFF
+	if _, bIsNoise = dsp.NormalizeU16_ac_threshold(uBuf, noiseThresh); bIsNoise {
+		return nil, nil, nil, true
+	}
+	U16SpectRef, bIsNoise = dsp.CreateU16SpectConfig( uBuf, HammingFftPoints, p.Cfg, noiseThresh )
+	if bIsNoise {
+		return nil, nil, nil, true
+	}
+	iSpectRefReduced, iSpectRefReducedPoolAvg = ReduceConfig( U16SpectRef, p.Cfg )
+	return iSpectRefReduced, iSpectRefReducedPoolAvg, U16SpectRef, false
+} // end func EnrollTake
+
+// EnrollWord builds reference word 'label' from 'takes', each a ReduceWordDetectCreateRef
+// reduction of the same word. Returns the reference and indices of rejected takes.
+// With two takes neither can be singled out, and both are accepted.
+func EnrollWord( label string, takes [][][]int, p EnrollParams ) (ref RefWord, rejected []int, err error) {
+	ref.Label = label
+	if len(takes) == 0 {
+		return ref, nil, ErrEnroll
+	}
+	// mean square error of each take to the others
+	meanErr := make([]int, len(takes))
+	for i,_ := range takes {
+		for j,_ := range takes {
+			if i != j {
+				meanErr[i] += SquareErr( takes[i], takes[j] )
+			}
+		}
+		if len(takes) > 1 {
+			meanErr[i] /= len(takes)-1
+		}
+	}
+	sorted := append([]int(nil), meanErr...)
+	sort.Ints(sorted)
+	median := sorted[len(sorted)/2]
+
+	accepted := make([][][]int, 0, len(takes))
+	for i,_ := range takes {
+		if len(takes) > 2 && meanErr[i] > p.RejectFloor && float64(meanErr[i]) > p.RejectFactor*float64(median) {
+			rejected = append(rejected, i)
+			continue
+		}
+		accepted = append(accepted, takes[i])
+	}
+	if len(accepted) < p.MinAccepted || len(accepted) == 0 {
+		return ref, rejected, ErrEnroll
+	}
+	ref.Reduced = AverageInt(accepted)
+	if p.KeepTakes {
+		ref.Takes = accepted
+	}
+	return ref, rejected, nil
+} // end func EnrollWord
+
+// AverageInt returns the element by element average of equal size arrays 'arrs'
+func AverageInt( arrs [][][]int ) (avg [][]int) {
+	avg = make([][]int, len(arrs[0]))
+	for i,_ := range avg {
+		avg[i] = make([]int, len(arrs[0][0]))
+		for j,_ := range avg[i] {
+			sum := 0
+			for k,_ := range arrs {
+				sum += arrs[k][i][j]
+			}
+			avg[i][j] = sum / len(arrs)
+		}
+	}
+	return avg
+}
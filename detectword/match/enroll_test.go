@@ -0,0 +1,130 @@
+// @file TinyGo/detectword/match/enroll_test.go
+// @date 2026.10.18
+// @info EnrollWord median based take rejection, MinAccepted and KeepTakes; EnrollTake noise
+//       and reduction
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package match
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"localhost/detectword/dsp"
+)
+
+// take returns a 2x2 reduction, 'v' in its first cell
+func take(v int) [][]int {
+	return [][]int{{v, 0}, {0, 0}}
+}
+
+// testEnroll rejects a take over 1.5 x the median mean error and over 200; 2 accepted
+var testEnroll = EnrollParams{Takes: 3, RejectFactor: 1.5, RejectFloor: 200, MinAccepted: 2}
+
+func TestEnrollWord(t *testing.T) {
+	p := testEnroll
+	noFloor := p
+	noFloor.RejectFloor = 0
+	keep := p
+	keep.KeepTakes = true
+	strict := p
+	strict.MinAccepted = 3
+	single := p
+	single.Takes, single.MinAccepted = 1, 1
+
+	tests := []struct {
+		name     string
+		takes    [][][]int
+		p        EnrollParams
+		rejected []int
+		reduced  [][]int
+		err      error
+	}{
+		// mean errors 5050, 4100, 9050; median 5050, 9050 > 7575
+		{"outlier rejected", [][][]int{take(0), take(10), take(100)}, p, []int{2}, take(5), nil},
+		{"outlier first", [][][]int{take(100), take(0), take(10)}, p, []int{0}, take(5), nil},
+		// mean errors 50, 50, 100; over 1.5 x median, but within RejectFloor
+		// mean errors 5525 x 3, 7525, 9100; median 5525, only 9100 > 8287
+		{"median not mean", [][][]int{take(0), take(0), take(0), take(100), take(110)}, p, []int{4}, take(25), nil},
+		// mean errors 13500, 12100, 10966, 36166; the upper median 13500 of four
+		{"four takes", [][][]int{take(0), take(10), take(20), take(200)}, p, []int{3}, take(10), nil},
+		// mean errors all 6666; two pairs, neither singled out
+		{"two pairs", [][][]int{take(0), take(0), take(100), take(100)}, p, nil, take(50), nil},
+		{"near takes kept", [][][]int{take(0), take(0), take(10)}, p, nil, take(3), nil},
+		{"near takes without floor", [][][]int{take(0), take(0), take(10)}, noFloor, []int{2}, take(0), nil},
+		{"agreeing takes", [][][]int{take(40), take(50), take(60)}, p, nil, take(50), nil},
+		{"two takes both kept", [][][]int{take(0), take(100)}, p, nil, take(50), nil},
+		{"one take", [][][]int{take(7)}, p, nil, nil, ErrEnroll}, // MinAccepted 2
+		{"too few accepted", [][][]int{take(0), take(10), take(100)}, strict, []int{2}, nil, ErrEnroll},
+		{"one take accepted", [][][]int{take(7)}, single, nil, take(7), nil},
+		{"no takes", nil, p, nil, nil, ErrEnroll},
+	}
+	for _, tt := range tests {
+		ref, rejected, err := EnrollWord("on", tt.takes, tt.p)
+		if err != tt.err || !reflect.DeepEqual(rejected, tt.rejected) {
+			t.Errorf("%s: rejected %v, %v, want %v, %v", tt.name, rejected, err, tt.rejected, tt.err)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(ref.Reduced, tt.reduced) {
+			t.Errorf("%s: Reduced %v, want %v", tt.name, ref.Reduced, tt.reduced)
+		}
+		if ref.Label != "on" {
+			t.Errorf("%s: Label %q", tt.name, ref.Label)
+		}
+	}
+}
+
+func TestEnrollWordKeepTakes(t *testing.T) {
+	takes := [][][]int{take(0), take(10), take(100)}
+	p := testEnroll
+	ref, _, err := EnrollWord("on", takes, p)
+	if err != nil || ref.Takes != nil {
+		t.Errorf("averaged: Takes %v, %v, want none", ref.Takes, err)
+	}
+	p.KeepTakes = true
+	ref, _, err = EnrollWord("on", takes, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the accepted takes are kept, and still averaged into Reduced
+	if want := [][][]int{take(0), take(10)}; !reflect.DeepEqual(ref.Takes, want) {
+		t.Errorf("kept Takes %v, want %v", ref.Takes, want)
+	}
+	if !reflect.DeepEqual(ref.Reduced, take(5)) {
+		t.Errorf("kept Reduced %v, want %v", ref.Reduced, take(5))
+	}
+}
+
+func TestEnrollTake(t *testing.T) {
+	p := DefaultEnrollParams()
+	hamming := dsp.Hamming(p.Cfg.FftPoints())
+	silence := make([]uint16, p.Cfg.BufSize)
+	word := make([]uint16, p.Cfg.BufSize)
+	for i := range silence {
+		silence[i] = 0x8000
+		word[i] = uint16(0x8000 + 0x6000*math.Sin(2*math.Pi*float64(i)/16))
+	}
+	if _, _, _, bIsNoise := EnrollTake(silence, hamming, dsp.NoiseThreshold, p); !bIsNoise {
+		t.Error("EnrollTake of silence is not noise")
+	}
+	if _, _, _, bIsNoise := EnrollTake(nil, hamming, dsp.NoiseThreshold, p); !bIsNoise {
+		t.Error("EnrollTake of an empty take is not noise")
+	}
+	reduced, poolAvg, spect, bIsNoise := EnrollTake(word, hamming, dsp.NoiseThreshold, p)
+	if bIsNoise {
+		t.Fatal("EnrollTake of a tone is noise")
+	}
+	if len(spect) != p.Cfg.Tbins || len(poolAvg) != p.Cfg.VBlocks || len(reduced) != p.Cfg.VBlocks2 {
+		t.Errorf("EnrollTake shapes: spect %d, pools %d, %d rows; want %d, %d, %d",
+			len(spect), len(poolAvg), len(reduced), p.Cfg.Tbins, p.Cfg.VBlocks, p.Cfg.VBlocks2)
+	}
+	if want, _ := ReduceConfig(spect, p.Cfg); !reflect.DeepEqual(reduced, want) {
+		t.Errorf("EnrollTake reduction %v, want ReduceConfig of its spectrogram %v", reduced, want)
+	}
+}
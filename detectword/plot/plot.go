@@ -0,0 +1,263 @@
+// @file TinyGo/detectword/plot/plot.go
+// @date 2026.10.18
+// @info heatmap and line plots as images with titled, ticked and labelled axes, and a
+//       blue to red colormap; spectrogram, pool and waveform plots without GNU Octave
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+
+package plot
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"strconv"
+)
+
+// Image size and margins around the plot area, in pixels
+const (
+	Width  = 640
+	Height = 400
+	left   = 72
+	top    = 28
+	bottom = 44
+	right  = 24
+	cbar   = 64 // right margin holding the heatmap colorbar
+)
+
+var (
+	white = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	black = color.RGBA{0x00, 0x00, 0x00, 0xFF}
+	grey  = color.RGBA{0xC0, 0xC0, 0xC0, 0xFF}
+	trace = color.RGBA{0x00, 0x40, 0xC0, 0xFF}
+)
+
+// Axis is the value range and label of a plot axis
+type Axis struct {
+	Min, Max float64
+	Label    string // e.g. "time ms"
+}
+
+// Colormap returns the color of 't', 0 blue through cyan, green and yellow to 1 red, as
+// Octave's jet; 't' is clipped to 0..1
+func Colormap(t float64) color.RGBA {
+	if t < 0 {
+		t = 0
+	}
+	if t > 1 {
+		t = 1
+	}
+	ramp := func(c float64) uint8 { // a trapezoid of width 0.75 centered on 'c'
+		v := 1.5 - math.Abs(4*t-c)
+		if v < 0 {
+			v = 0
+		}
+		if v > 1 {
+			v = 1
+		}
+		return uint8(v*255 + 0.5)
+	}
+	return color.RGBA{ramp(3), ramp(2), ramp(1), 0xFF}
+}
+
+// Heatmap plots 'm' with m[i] along the x axis and m[i][j] up the y axis, each cell colored
+// by Colormap of its value between the smallest and largest of 'm'; a colorbar shows the
+// values
+func Heatmap(m [][]int, x, y Axis, title string) *image.RGBA {
+	img, area := frame(title, cbar)
+	lo, hi := bounds(m)
+	span := float64(hi - lo)
+	if span == 0 {
+		span = 1
+	}
+	rows := len(m)
+	for px := area.Min.X; px < area.Max.X; px++ {
+		i := (px - area.Min.X) * rows / area.Dx()
+		cols := len(m[i])
+		for py := area.Min.Y; py < area.Max.Y; py++ {
+			j := (area.Max.Y - 1 - py) * cols / area.Dy() // j 0 at the bottom
+			img.Set(px, py, Colormap(float64(m[i][j]-lo)/span))
+		}
+	}
+	axes(img, area, x, y)
+
+	// colorbar
+	bar := image.Rect(area.Max.X+12, area.Min.Y, area.Max.X+24, area.Max.Y)
+	for py := bar.Min.Y; py < bar.Max.Y; py++ {
+		c := Colormap(float64(bar.Max.Y-1-py) / float64(bar.Dy()-1))
+		fill(img, bar.Min.X, py, bar.Dx(), 1, c)
+	}
+	box(img, bar)
+	Text(img, bar.Max.X+4, bar.Min.Y, strconv.Itoa(hi), black, 1)
+	Text(img, bar.Max.X+4, bar.Max.Y-GlyphH, strconv.Itoa(lo), black, 1)
+	return img
+}
+
+// Line plots 'ys' against their index, mapped linearly onto the x axis; values outside
+// the y axis are clipped
+func Line(ys []float64, x, y Axis, title string) *image.RGBA {
+	img, area := frame(title, right)
+	if y.Min < 0 && y.Max > 0 { // zero line
+		zy := yPixel(0, area, y)
+		fill(img, area.Min.X, zy, area.Dx(), 1, grey)
+	}
+	n := len(ys)
+	px0, py0 := 0, 0
+	for k, v := range ys {
+		px := area.Min.X
+		if n > 1 {
+			px += k * (area.Dx() - 1) / (n - 1)
+		}
+		py := yPixel(v, area, y)
+		if k > 0 {
+			line(img, px0, py0, px, py, trace)
+		}
+		px0, py0 = px, py
+	}
+	axes(img, area, x, y)
+	return img
+}
+
+// WritePNG writes 'img' to 'filename'
+func WritePNG(filename string, img image.Image) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// frame returns a white image with 'title', and its plot area inside a right margin 'r'
+func frame(title string, r int) (*image.RGBA, image.Rectangle) {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	fill(img, 0, 0, Width, Height, white)
+	Text(img, (Width-TextWidth(title, 2))/2, 6, title, black, 2)
+	return img, image.Rect(left, top, Width-r, Height-bottom)
+}
+
+// axes draws the plot area box, ticks with values, and the axis labels
+func axes(img *image.RGBA, area image.Rectangle, x, y Axis) {
+	box(img, area)
+	for _, v := range ticks(x.Min, x.Max) {
+		px := area.Min.X + int(math.Round((v-x.Min)/(x.Max-x.Min)*float64(area.Dx()-1)))
+		fill(img, px, area.Max.Y, 1, 4, black)
+		s := tickLabel(v, x)
+		Text(img, px-TextWidth(s, 1)/2, area.Max.Y+7, s, black, 1)
+	}
+	for _, v := range ticks(y.Min, y.Max) {
+		py := yPixel(v, area, y)
+		fill(img, area.Min.X-4, py, 4, 1, black)
+		s := tickLabel(v, y)
+		Text(img, area.Min.X-7-TextWidth(s, 1), py-GlyphH/2, s, black, 1)
+	}
+	Text(img, area.Min.X+(area.Dx()-TextWidth(x.Label, 1))/2, area.Max.Y+22, x.Label, black, 1)
+	TextUp(img, 8, area.Min.Y+(area.Dy()+TextWidth(y.Label, 1))/2, y.Label, black, 1)
+}
+
+// yPixel returns the image row of value 'v' on axis 'y', clipped to the plot area
+func yPixel(v float64, area image.Rectangle, y Axis) int {
+	if y.Max == y.Min {
+		return area.Max.Y - 1
+	}
+	py := area.Max.Y - 1 - int(math.Round((v-y.Min)/(y.Max-y.Min)*float64(area.Dy()-1)))
+	if py < area.Min.Y {
+		py = area.Min.Y
+	}
+	if py > area.Max.Y-1 {
+		py = area.Max.Y - 1
+	}
+	return py
+}
+
+// ticks returns about 5 round values, steps of 1, 2 or 5 x 10^n, between 'lo' and 'hi'
+func ticks(lo, hi float64) (t []float64) {
+	if !(hi > lo) {
+		return []float64{lo}
+	}
+	step := math.Pow(10, math.Floor(math.Log10((hi-lo)/5)))
+	for _, f := range []float64{1, 2, 5, 10} {
+		if (hi-lo)/(step*f) <= 6 {
+			step *= f
+			break
+		}
+	}
+	for v := math.Ceil(lo/step) * step; v <= hi+step*1e-9; v += step {
+		t = append(t, math.Round(v/step)*step)
+	}
+	return t
+}
+
+// tickLabel formats tick 'v' of axis 'a' with the precision of its tick step
+func tickLabel(v float64, a Axis) string {
+	t := ticks(a.Min, a.Max)
+	prec := 0
+	if len(t) > 1 {
+		for step := t[1] - t[0]; step < 1 && prec < 6; step *= 10 {
+			prec++
+		}
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
+
+// bounds returns the smallest and largest value of 'm'
+func bounds(m [][]int) (lo, hi int) {
+	first := true
+	for _, r := range m {
+		for _, v := range r {
+			if first || v < lo {
+				lo = v
+			}
+			if first || v > hi {
+				hi = v
+			}
+			first = false
+		}
+	}
+	return lo, hi
+}
+
+// box draws the outline of 'r'
+func box(img *image.RGBA, r image.Rectangle) {
+	fill(img, r.Min.X-1, r.Min.Y-1, r.Dx()+2, 1, black)
+	fill(img, r.Min.X-1, r.Max.Y, r.Dx()+2, 1, black)
+	fill(img, r.Min.X-1, r.Min.Y-1, 1, r.Dy()+2, black)
+	fill(img, r.Max.X, r.Min.Y-1, 1, r.Dy()+2, black)
+}
+
+// line draws a line from 'x0', 'y0' to 'x1', 'y1'; Bresenham
+func line(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx, sx := x1-x0, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	dy, sy := y1-y0, 1
+	if dy < 0 {
+		dy, sy = -dy, -1
+	}
+	e := dx - dy
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 > -dy {
+			e -= dy
+			x0 += sx
+		}
+		if e2 < dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
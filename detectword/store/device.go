@@ -0,0 +1,116 @@
+// @file TinyGo/detectword/store/device.go
+// @date 2026.10.18
+// @info host BlockDevices; MemDevice behaves as nor flash, FileDevice persists one to a file,
+//       e.g. a flash image shared with host tools
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go build
+
+package store
+
+import (
+	"fmt"
+	"os"
+)
+
+// rp2040 flash geometry, as reported by tinygo's machine.Flash
+const (
+	PicoWriteBlockSize = 256
+	PicoEraseBlockSize = 4096
+)
+
+// MemDevice is an in memory nor flash: erased bytes are 0xFF, writes only clear bits,
+// and writes must be write block aligned; a missing erase corrupts data as on the pico
+type MemDevice struct {
+	Data       []byte
+	WriteBlock int64
+	EraseBlock int64
+}
+
+// NewMemDevice returns an erased MemDevice of 'size' bytes with pico block sizes
+func NewMemDevice(size int64) *MemDevice {
+	m := &MemDevice{Data: make([]byte, size), WriteBlock: PicoWriteBlockSize, EraseBlock: PicoEraseBlockSize}
+	for i := range m.Data {
+		m.Data[i] = 0xFF
+	}
+	return m
+}
+
+func (m *MemDevice) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 || off+int64(len(p)) > int64(len(m.Data)) {
+		return 0, fmt.Errorf("store: read %d bytes at %d beyond device size %d", len(p), off, len(m.Data))
+	}
+	return copy(p, m.Data[off:]), nil
+}
+
+func (m *MemDevice) WriteAt(p []byte, off int64) (n int, err error) {
+	if off%m.WriteBlock != 0 || int64(len(p))%m.WriteBlock != 0 {
+		return 0, fmt.Errorf("store: write %d bytes at %d not %d byte aligned", len(p), off, m.WriteBlock)
+	}
+	if off < 0 || off+int64(len(p)) > int64(len(m.Data)) {
+		return 0, fmt.Errorf("store: write %d bytes at %d beyond device size %d", len(p), off, len(m.Data))
+	}
+	for i, v := range p {
+		m.Data[off+int64(i)] &= v // nor flash programming clears bits only
+	}
+	return len(p), nil
+}
+
+func (m *MemDevice) Size() int64           { return int64(len(m.Data)) }
+func (m *MemDevice) WriteBlockSize() int64 { return m.WriteBlock }
+func (m *MemDevice) EraseBlockSize() int64 { return m.EraseBlock }
+
+// EraseBlocks sets 'len' erase blocks from block 'start' to 0xFF
+func (m *MemDevice) EraseBlocks(start, len int64) error {
+	lo, hi := start*m.EraseBlock, (start+len)*m.EraseBlock
+	if lo < 0 || hi > m.Size() {
+		return fmt.Errorf("store: erase blocks %d-%d beyond device size %d", start, start+len, m.Size())
+	}
+	for i := lo; i < hi; i++ {
+		m.Data[i] = 0xFF
+	}
+	return nil
+}
+
+// FileDevice is a MemDevice loaded from, and written through to, file Name
+type FileDevice struct {
+	MemDevice
+	Name string
+}
+
+// OpenFileDevice opens flash image 'name', creating an erased image of 'size' bytes if it
+// does not exist
+func OpenFileDevice(name string, size int64) (*FileDevice, error) {
+	f := &FileDevice{MemDevice: *NewMemDevice(size), Name: name}
+	data, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return f, f.sync()
+	}
+	if err != nil {
+		return nil, err
+	}
+	f.Data = data
+	return f, nil
+}
+
+func (f *FileDevice) WriteAt(p []byte, off int64) (n int, err error) {
+	if n, err = f.MemDevice.WriteAt(p, off); err != nil {
+		return n, err
+	}
+	return n, f.sync()
+}
+
+func (f *FileDevice) EraseBlocks(start, len int64) error {
+	if err := f.MemDevice.EraseBlocks(start, len); err != nil {
+		return err
+	}
+	return f.sync()
+}
+
+// sync writes the image to file Name
+func (f *FileDevice) sync() error {
+	return os.WriteFile(f.Name, f.Data, 0644)
+}
@@ -0,0 +1,381 @@
+// @file TinyGo/detectword/store/store.go
+// @date 2026.10.18
+// @info persist enrolled reference words, and the parameters they were built with, as a
+//       versioned, checksummed record on a block device, e.g. a reserved region of pico flash
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 exported Decoder and EncodeRefWord for detectword/template
+// @date 2026.10.18 ParamsOf() config.Config
+// @date 2026.10.18 version 2; Params FrameLen, overlapping fft frames. Version 1 records read as FrameLen 0
+// @date 2026.10.18 version 3; Params Spect, the spectrogram kind. Older records read as Spect 0
+// @date 2026.10.18 version 4; Params mel front end. Older records read as 0, as ParamsOf any
+//                  Spect but mel
+// @date 2026.10.18 EncodeMatrix returns an error past 255 rows or cols; Decode rejects
+//                  trailing payload bytes
+// @date 2026.10.18 ParamsOf MelFilters is config.MelFilterCount(), the filters MelFilters 0 derives
+
+// @build: go build, or tinygo as a dependency of detectword_pico
+
+// Record layout, little endian:
+//
+//	offset size
+//	0      4    magic "DWTR"
+//	4      2    version
+//	6      2    reserved, 0
+//	8      4    payload length in bytes
+//	12     4    crc32 (IEEE) of payload
+//	16     ...  payload
+//
+// payload: Params as 17 uint16 (Tbins, Fbins, BufSize, SleepTime, VBlocks, HBlocks,
+// VBlocks2, HBlocks2, SpectThresh, FrameLen, Spect, MelFilters, MelLowHz, MelHighHz, MFCCs,
+// Lifter, Deltas; version 1 the first 9, version 2 the first 10, version 3 the first 11), uint8 word count, then per word: uint8 label length, label, matrix Reduced,
+// uint8 take count, matrix per take. A matrix is uint8 rows, uint8 cols, then rows x cols
+// int32.
+
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"localhost/detectword/config"
+	"localhost/detectword/match"
+)
+
+const Magic = "DWTR"
+const Version = 4
+const headerSize = 16
+
+var (
+	ErrNoRecord = errors.New("store: no record") // erased or never written
+	ErrVersion  = errors.New("store: unsupported record version")
+	ErrChecksum = errors.New("store: record checksum mismatch")
+	ErrCorrupt  = errors.New("store: record corrupt")
+)
+
+// Params are the capture, spectrogram and reduction parameters reference words were
+// built with; references only match captures processed with equal Params
+type Params struct {
+	Tbins, Fbins       int
+	BufSize, SleepTime int // samples, us
+	VBlocks, HBlocks   int // avg pool block size
+	VBlocks2, HBlocks2 int // peak pool block size
+	SpectThresh        uint16
+	FrameLen           int // fft frame points, 0 BufSize/Tbins; the hop is (BufSize-FrameLen)/(Tbins-1)
+	Spect              int // dsp.SpectKind
+	MelFilters         int // mel front end, config.Config's, MelFilterCount(); all 0 unless Spect is dsp.SpectMel
+	MelLowHz           int
+	MelHighHz          int
+	MFCCs              int
+	Lifter             int
+	Deltas             int
+}
+
+// ParamsOf returns the Params of config 'c'; the mel front end fields only with Spect 3,
+// dsp.SpectMel, the only spectrogram they change
+func ParamsOf(c config.Config) Params {
+	prm := Params{Tbins: c.Tbins, Fbins: c.Fbins, BufSize: c.BufSize, SleepTime: c.SleepTime,
+		VBlocks: c.VBlocks, HBlocks: c.HBlocks, VBlocks2: c.VBlocks2, HBlocks2: c.HBlocks2,
+		SpectThresh: c.SpectThresh, FrameLen: c.FrameLen, Spect: c.Spect}
+	if c.Spect == 3 {
+		prm.MelFilters, prm.MelLowHz, prm.MelHighHz = c.MelFilterCount(), c.MelLowHz, c.MelHighHz
+		prm.MFCCs, prm.Lifter, prm.Deltas = c.MFCCs, c.Lifter, c.Deltas
+	}
+	return prm
+}
+
+// Record is the stored state: enrolled reference words and their Params
+type Record struct {
+	Params Params
+	Refs   []match.RefWord
+}
+
+// Matches is true when 'rec' holds references for words 'labels', in order, built with 'prm'
+func (rec Record) Matches(prm Params, labels []string) bool {
+	if rec.Params != prm || len(rec.Refs) != len(labels) {
+		return false
+	}
+	for i, ref := range rec.Refs {
+		if ref.Label != labels[i] || len(ref.Reduced) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BlockDevice is the flash interface Save and Load use; tinygo's machine.Flash
+// implements it, as do MemDevice and FileDevice for the host
+type BlockDevice interface {
+	ReadAt(p []byte, off int64) (n int, err error)
+	WriteAt(p []byte, off int64) (n int, err error)
+	Size() int64
+	WriteBlockSize() int64
+	EraseBlockSize() int64
+	EraseBlocks(start, len int64) error
+}
+
+// Encode returns 'rec' as a record, header and payload
+func Encode(rec Record) ([]byte, error) {
+	if len(rec.Refs) > match.MaxRefWords {
+		return nil, fmt.Errorf("store: %d words, max %d", len(rec.Refs), match.MaxRefWords)
+	}
+	p := make([]byte, 0, 256)
+	p = EncodeParams(p, rec.Params)
+	p = append(p, uint8(len(rec.Refs)))
+	for _, ref := range rec.Refs {
+		var err error
+		if p, err = EncodeRefWord(p, ref); err != nil {
+			return nil, err
+		}
+	}
+	b := make([]byte, headerSize, headerSize+len(p))
+	copy(b[0:4], Magic)
+	binary.LittleEndian.PutUint16(b[4:6], Version)
+	binary.LittleEndian.PutUint32(b[8:12], uint32(len(p)))
+	binary.LittleEndian.PutUint32(b[12:16], crc32.ChecksumIEEE(p))
+	return append(b, p...), nil
+} // end func Encode
+
+// Decode returns the Record in 'b', a record written by Encode
+func Decode(b []byte) (rec Record, err error) {
+	payload, version, err := checkHeader(b)
+	if err != nil {
+		return rec, err
+	}
+	d := NewDecoder(payload)
+	rec.Params = d.ParamsVersion(version)
+	rec.Refs = make([]match.RefWord, d.U8())
+	for i, _ := range rec.Refs {
+		rec.Refs[i] = d.RefWord()
+	}
+	if d.Err() != nil {
+		return Record{}, d.Err()
+	}
+	if d.Len() != 0 {
+		return Record{}, ErrCorrupt // bytes after the last word
+	}
+	return rec, nil
+} // end func Decode
+
+// checkHeader validates the header of 'b' and returns the checksummed payload and its
+// version, 1 to Version
+func checkHeader(b []byte) (payload []byte, version uint16, err error) {
+	if len(b) < headerSize || string(b[0:4]) != Magic {
+		return nil, 0, ErrNoRecord
+	}
+	version = binary.LittleEndian.Uint16(b[4:6])
+	if version < 1 || version > Version {
+		return nil, 0, ErrVersion
+	}
+	n := int(binary.LittleEndian.Uint32(b[8:12]))
+	if n > len(b)-headerSize {
+		return nil, 0, ErrCorrupt
+	}
+	payload = b[headerSize : headerSize+n]
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(b[12:16]) {
+		return nil, 0, ErrChecksum
+	}
+	return payload, version, nil
+}
+
+// Save erases the blocks 'rec' needs at the start of 'dev' and writes it there
+func Save(dev BlockDevice, rec Record) error {
+	b, err := Encode(rec)
+	if err != nil {
+		return err
+	}
+	if wbs := dev.WriteBlockSize(); len(b)%int(wbs) != 0 { // pad to whole write blocks, erased value
+		pad := int(wbs) - len(b)%int(wbs)
+		for i := 0; i < pad; i++ {
+			b = append(b, 0xFF)
+		}
+	}
+	if int64(len(b)) > dev.Size() {
+		return fmt.Errorf("store: record %d bytes, device %d", len(b), dev.Size())
+	}
+	ebs := dev.EraseBlockSize()
+	if err = dev.EraseBlocks(0, (int64(len(b))+ebs-1)/ebs); err != nil {
+		return err
+	}
+	_, err = dev.WriteAt(b, 0)
+	return err
+} // end func Save
+
+// Load reads and validates the Record at the start of 'dev'; ErrNoRecord when erased
+func Load(dev BlockDevice) (rec Record, err error) {
+	hdr := make([]byte, headerSize)
+	if _, err = dev.ReadAt(hdr, 0); err != nil {
+		return rec, err
+	}
+	if string(hdr[0:4]) != Magic {
+		return rec, ErrNoRecord
+	}
+	n := int64(binary.LittleEndian.Uint32(hdr[8:12]))
+	if n > dev.Size()-headerSize {
+		return rec, ErrCorrupt
+	}
+	b := make([]byte, headerSize+n)
+	if _, err = dev.ReadAt(b, 0); err != nil {
+		return rec, err
+	}
+	return Decode(b)
+} // end func Load
+
+// EncodeParams appends 'prm' to 'b' as 17 uint16
+func EncodeParams(b []byte, prm Params) []byte {
+	for _, v := range []int{prm.Tbins, prm.Fbins, prm.BufSize, prm.SleepTime,
+		prm.VBlocks, prm.HBlocks, prm.VBlocks2, prm.HBlocks2, int(prm.SpectThresh), prm.FrameLen,
+		prm.Spect, prm.MelFilters, prm.MelLowHz, prm.MelHighHz, prm.MFCCs, prm.Lifter, prm.Deltas} {
+		b = appendUint16(b, uint16(v))
+	}
+	return b
+}
+
+// EncodeRefWord appends 'ref' to 'b': uint8 label length, label, matrix Reduced, uint8
+// take count, matrix per take
+func EncodeRefWord(b []byte, ref match.RefWord) ([]byte, error) {
+	if len(ref.Label) > 255 || len(ref.Takes) > 255 {
+		return nil, fmt.Errorf("store: word %q too large", ref.Label)
+	}
+	b = append(b, uint8(len(ref.Label)))
+	b = append(b, ref.Label...)
+	b, err := EncodeMatrix(b, ref.Reduced)
+	if err != nil {
+		return nil, fmt.Errorf("store: word %q: %v", ref.Label, err)
+	}
+	b = append(b, uint8(len(ref.Takes)))
+	for _, take := range ref.Takes {
+		if b, err = EncodeMatrix(b, take); err != nil {
+			return nil, fmt.Errorf("store: word %q take: %v", ref.Label, err)
+		}
+	}
+	return b, nil
+}
+
+// EncodeMatrix appends 'm' to 'b' as uint8 rows, uint8 cols, int32 values; an error when
+// 'm' has over 255 rows or cols, or rows of differing length
+func EncodeMatrix(b []byte, m [][]int) ([]byte, error) {
+	cols := 0
+	if len(m) > 0 {
+		cols = len(m[0])
+	}
+	if len(m) > 255 || cols > 255 {
+		return nil, fmt.Errorf("matrix %dx%d, max 255x255", len(m), cols)
+	}
+	for i, _ := range m {
+		if len(m[i]) != cols {
+			return nil, fmt.Errorf("matrix row %d has %d cols, want %d", i, len(m[i]), cols)
+		}
+	}
+	b = append(b, uint8(len(m)), uint8(cols))
+	for i, _ := range m {
+		for j := 0; j < cols; j++ {
+			b = appendUint32(b, uint32(int32(m[i][j])))
+		}
+	}
+	return b, nil
+}
+
+// appendUint16 and appendUint32 append little endian values; binary.LittleEndian.Append*
+// are go 1.19, newer than tinygo v0.21
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// Decoder reads Encode* fields from a payload in order; the first short read sets Err()
+type Decoder struct {
+	b   []byte
+	err error
+}
+
+// NewDecoder returns a Decoder reading 'b'
+func NewDecoder(b []byte) *Decoder {
+	return &Decoder{b: b}
+}
+
+// Err returns ErrCorrupt after a read past the end of the payload
+func (d *Decoder) Err() error { return d.err }
+
+// Len returns the count of unread bytes
+func (d *Decoder) Len() int { return len(d.b) }
+
+func (d *Decoder) bytes(n int) []byte {
+	if d.err != nil || n > len(d.b) {
+		d.err = ErrCorrupt
+		return make([]byte, n)
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *Decoder) u8() uint8   { return d.bytes(1)[0] }
+func (d *Decoder) u16() uint16 { return binary.LittleEndian.Uint16(d.bytes(2)) }
+func (d *Decoder) i32() int    { return int(int32(binary.LittleEndian.Uint32(d.bytes(4)))) }
+
+// U8 reads one byte
+func (d *Decoder) U8() uint8 { return d.u8() }
+
+// Label reads a uint8 length prefixed string
+func (d *Decoder) Label() string { return string(d.bytes(int(d.u8()))) }
+
+// Params reads EncodeParams output
+func (d *Decoder) Params() Params {
+	return d.ParamsVersion(Version)
+}
+
+// ParamsVersion reads the Params of a 'version' record; version 1 has no FrameLen, version 2
+// no Spect, version 3 no mel front end, read as 0
+func (d *Decoder) ParamsVersion(version uint16) (prm Params) {
+	prm.Tbins, prm.Fbins = int(d.u16()), int(d.u16())
+	prm.BufSize, prm.SleepTime = int(d.u16()), int(d.u16())
+	prm.VBlocks, prm.HBlocks = int(d.u16()), int(d.u16())
+	prm.VBlocks2, prm.HBlocks2 = int(d.u16()), int(d.u16())
+	prm.SpectThresh = d.u16()
+	if version >= 2 {
+		prm.FrameLen = int(d.u16())
+	}
+	if version >= 3 {
+		prm.Spect = int(d.u16())
+	}
+	if version >= 4 {
+		prm.MelFilters, prm.MelLowHz, prm.MelHighHz = int(d.u16()), int(d.u16()), int(d.u16())
+		prm.MFCCs, prm.Lifter, prm.Deltas = int(d.u16()), int(d.u16()), int(d.u16())
+	}
+	return prm
+}
+
+// Matrix reads EncodeMatrix output
+func (d *Decoder) Matrix() [][]int {
+	rows, cols := int(d.u8()), int(d.u8())
+	m := make([][]int, rows)
+	for i, _ := range m {
+		m[i] = make([]int, cols)
+		for j, _ := range m[i] {
+			m[i][j] = d.i32()
+		}
+	}
+	return m
+}
+
+// RefWord reads EncodeRefWord output
+func (d *Decoder) RefWord() (ref match.RefWord) {
+	ref.Label = d.Label()
+	ref.Reduced = d.Matrix()
+	if nTakes := int(d.u8()); nTakes > 0 {
+		ref.Takes = make([][][]int, nTakes)
+		for k, _ := range ref.Takes {
+			ref.Takes[k] = d.Matrix()
+		}
+	}
+	return ref
+}
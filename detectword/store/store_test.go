@@ -0,0 +1,237 @@
+// @file TinyGo/detectword/store/store_test.go
+// @date 2026.10.18
+// @info Save/Load round trip on a MemDevice, and the erased, checksum, corrupt, trailing
+//       byte and old version paths; EncodeMatrix size limits
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package store
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"reflect"
+	"testing"
+
+	"localhost/detectword/config"
+	"localhost/detectword/match"
+)
+
+func testRecord() Record {
+	c := config.Default()
+	c.Spect, c.FrameLen, c.Hop, c.BufSize, c.MFCCs = 3, 64, 16, 1072, 13
+	c.DeriveTbins()
+	return Record{Params: ParamsOf(c), Refs: []match.RefWord{
+		{Label: "on", Reduced: [][]int{{1, 2}, {3, -4}}},
+		{Label: "off", Reduced: [][]int{{5, 6}, {7, 8}},
+			Takes: [][][]int{{{5, 6}, {7, 9}}, {{-70000, 6}, {7, 7}}}},
+		{Label: "dim", Reduced: [][]int{}},
+	}}
+}
+
+// testDevice is a small pico MemDevice
+func testDevice() *MemDevice {
+	return NewMemDevice(4 * PicoEraseBlockSize)
+}
+
+func TestSaveLoad(t *testing.T) {
+	dev := testDevice()
+	want := testRecord()
+	if err := Save(dev, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load = %+v\nwant %+v", got, want)
+	}
+
+	// a second, shorter record replaces the first; Save erases before writing
+	short := Record{Params: want.Params, Refs: want.Refs[:1]}
+	if err = Save(dev, short); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = Load(dev); err != nil || !reflect.DeepEqual(got, short) {
+		t.Errorf("Load after overwrite = %+v, %v, want %+v", got, err, short)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	rec := testRecord()
+	rec.Refs = rec.Refs[:2]
+	other := rec.Params
+	other.Tbins++
+	tests := []struct {
+		name   string
+		prm    Params
+		labels []string
+		want   bool
+	}{
+		{"same", rec.Params, []string{"on", "off"}, true},
+		{"params differ", other, []string{"on", "off"}, false},
+		{"labels differ", rec.Params, []string{"off", "on"}, false},
+		{"more words", rec.Params, []string{"on", "off", "dim"}, false},
+	}
+	empty := testRecord()
+	if empty.Matches(empty.Params, []string{"on", "off", "dim"}) {
+		t.Errorf("Matches with an empty dim reduction, want false")
+	}
+	for _, tt := range tests {
+		if got := rec.Matches(tt.prm, tt.labels); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// record returns a record of 'version' with 'payload' and a valid checksum
+func record(version uint16, payload []byte) []byte {
+	b := make([]byte, headerSize, headerSize+len(payload))
+	copy(b[0:4], Magic)
+	binary.LittleEndian.PutUint16(b[4:6], version)
+	binary.LittleEndian.PutUint32(b[8:12], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(b[12:16], crc32.ChecksumIEEE(payload))
+	return append(b, payload...)
+}
+
+func TestLoadErrors(t *testing.T) {
+	good, err := Encode(testRecord())
+	if err != nil {
+		t.Fatal(err)
+	}
+	flip := func(i int) []byte {
+		b := append([]byte(nil), good...)
+		b[i] ^= 0x01
+		return b
+	}
+	short := append([]byte(nil), good...)
+	binary.LittleEndian.PutUint32(short[8:12], 1<<20)
+	truncated := EncodeParams(nil, testRecord().Params)
+	truncated = append(truncated, 1, 2, 'o') // one word, label length 2, one byte of label
+
+	tests := []struct {
+		name string
+		b    []byte // nil is an erased device
+		want error
+	}{
+		{"erased", nil, ErrNoRecord},
+		{"bad magic", flip(0), ErrNoRecord},
+		{"payload crc", flip(headerSize + 3), ErrChecksum},
+		{"header crc", flip(12), ErrChecksum},
+		{"newer version", record(Version+1, good[headerSize:]), ErrVersion},
+		{"version 0", record(0, good[headerSize:]), ErrVersion},
+		{"length past device", short, ErrCorrupt},
+		{"truncated payload", record(Version, truncated), ErrCorrupt},
+		{"trailing bytes", record(Version, append(append([]byte(nil), good[headerSize:]...), 0)), ErrCorrupt},
+	}
+	for _, tt := range tests {
+		dev := testDevice()
+		copy(dev.Data, tt.b)
+		if _, err := Load(dev); err != tt.want {
+			t.Errorf("%s: Load error %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestEncodeMatrix(t *testing.T) {
+	square := func(rows, cols int) [][]int {
+		m := make([][]int, rows)
+		for i := range m {
+			m[i] = make([]int, cols)
+		}
+		return m
+	}
+	tests := []struct {
+		name string
+		m    [][]int
+		ok   bool
+	}{
+		{"empty", nil, true},
+		{"255x255", square(255, 255), true},
+		{"256 rows", square(256, 1), false},
+		{"256 cols", square(1, 256), false},
+		{"ragged", [][]int{{1, 2}, {3}}, false},
+	}
+	for _, tt := range tests {
+		b, err := EncodeMatrix(nil, tt.m)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: EncodeMatrix error %v, want ok %v", tt.name, err, tt.ok)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		d := NewDecoder(b)
+		if got := d.Matrix(); d.Err() != nil || d.Len() != 0 || len(got) != len(tt.m) {
+			t.Errorf("%s: Matrix of EncodeMatrix %d rows, %v, want %d", tt.name, len(got), d.Err(), len(tt.m))
+		}
+	}
+
+	// a reduction too large for the record is an Encode error, not a wrapped uint8
+	rec := testRecord()
+	rec.Refs[0].Reduced = square(1, 256)
+	if _, err := Encode(rec); err == nil {
+		t.Error("Encode of a 1x256 reduction, want an error")
+	}
+	rec = testRecord()
+	rec.Refs[1].Takes[1] = square(256, 1)
+	if _, err := Encode(rec); err == nil {
+		t.Error("Encode of a 256x1 take, want an error")
+	}
+}
+
+func TestLoadOldVersions(t *testing.T) {
+	prm := testRecord().Params
+	words := []byte{0} // no words
+	tests := []struct {
+		version uint16
+		params  int // uint16 params of the version
+		want    Params
+	}{
+		{1, 9, Params{Tbins: prm.Tbins, Fbins: prm.Fbins, BufSize: prm.BufSize, SleepTime: prm.SleepTime,
+			VBlocks: prm.VBlocks, HBlocks: prm.HBlocks, VBlocks2: prm.VBlocks2, HBlocks2: prm.HBlocks2,
+			SpectThresh: prm.SpectThresh}},
+		{2, 10, Params{Tbins: prm.Tbins, Fbins: prm.Fbins, BufSize: prm.BufSize, SleepTime: prm.SleepTime,
+			VBlocks: prm.VBlocks, HBlocks: prm.HBlocks, VBlocks2: prm.VBlocks2, HBlocks2: prm.HBlocks2,
+			SpectThresh: prm.SpectThresh, FrameLen: prm.FrameLen}},
+		{3, 11, Params{Tbins: prm.Tbins, Fbins: prm.Fbins, BufSize: prm.BufSize, SleepTime: prm.SleepTime,
+			VBlocks: prm.VBlocks, HBlocks: prm.HBlocks, VBlocks2: prm.VBlocks2, HBlocks2: prm.HBlocks2,
+			SpectThresh: prm.SpectThresh, FrameLen: prm.FrameLen, Spect: prm.Spect}},
+		{4, 17, prm},
+	}
+	for _, tt := range tests {
+		payload := append(EncodeParams(nil, prm)[:2*tt.params], words...)
+		dev := testDevice()
+		copy(dev.Data, record(tt.version, payload))
+		rec, err := Load(dev)
+		if err != nil {
+			t.Errorf("version %d: %v", tt.version, err)
+			continue
+		}
+		if rec.Params != tt.want || len(rec.Refs) != 0 {
+			t.Errorf("version %d: Params %+v, %d words, want %+v, 0 words", tt.version, rec.Params, len(rec.Refs), tt.want)
+		}
+	}
+}
+
+func TestParamsOfMel(t *testing.T) {
+	c := config.Default()
+	c.MelFilters = 20 // ignored unless Spect is mel
+	if prm := ParamsOf(c); prm.MelFilters != 0 || prm.Lifter != 0 {
+		t.Errorf("ParamsOf Spect %d has mel params %+v", c.Spect, prm)
+	}
+	c.Spect = 3
+	if prm := ParamsOf(c); prm.MelFilters != 20 || prm.Lifter != c.Lifter {
+		t.Errorf("ParamsOf Spect 3 = %+v, want MelFilters 20, Lifter %d", prm, c.Lifter)
+	}
+	// MelFilters 0 is stored as the filters it derives, 9 for the 16 point default fft
+	c.MelFilters = 0
+	if prm := ParamsOf(c); prm.MelFilters != 9 {
+		t.Errorf("ParamsOf Spect 3 MelFilters 0 = %d filters, want 9", prm.MelFilters)
+	}
+}
@@ -0,0 +1,167 @@
+// @file TinyGo/detectword/template/template.go
+// @date 2026.10.18
+// @info single reference word template files, for moving enrolled words between boards
+//       and host tools; the word decodes to the match.RefWord ReduceWordDetectCreateRef builds
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 version 2; store.Params FrameLen. Version 1 files read as FrameLen 0
+// @date 2026.10.18 version 3; store.Params Spect. Older files read as Spect 0
+// @date 2026.10.18 version 4; store.Params mel front end. Older files read as 0
+// @date 2026.10.18 Encode returns the store.EncodeMatrix error of a PoolAvg too large
+
+// @build: go build
+
+// Template file layout, little endian:
+//
+//	offset size
+//	0      4    magic "DWTF"
+//	4      2    version
+//	6      2    flags; bit 0 average pool matrix present
+//	8      4    payload length in bytes
+//	12     4    crc32 (IEEE) of payload
+//	16     ...  payload
+//
+// payload: Params as 17 uint16 (Tbins, Fbins, BufSize, SleepTime, VBlocks, HBlocks,
+// VBlocks2, HBlocks2, SpectThresh, FrameLen, Spect, MelFilters, MelLowHz, MelHighHz, MFCCs,
+// Lifter, Deltas; version 1 the first 9, version 2 the first 10, version 3 the first 11),
+// uint8 label length, label, matrix Reduced (the peak pooled int matrix), uint8
+// take count, matrix per take, then matrix PoolAvg when flag bit 0 is set. Params and
+// matrices are encoded as in a store record, see store.go; a matrix is uint8 rows, uint8
+// cols, then rows x cols int32.
+
+package template
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"localhost/detectword/match"
+	"localhost/detectword/store"
+)
+
+const Magic = "DWTF"
+const Version = 4
+const Ext = ".dwt"
+const headerSize = 16
+
+const flagPoolAvg = 1 << 0
+
+var (
+	ErrFormat   = errors.New("template: not a template file")
+	ErrVersion  = errors.New("template: unsupported template version")
+	ErrChecksum = errors.New("template: checksum mismatch")
+	ErrCorrupt  = errors.New("template: corrupt")
+)
+
+// Template is one enrolled reference word and the Params it was built with
+type Template struct {
+	Params  store.Params `json:"params"`
+	Label   string       `json:"label"`
+	Reduced [][]int      `json:"reduced"`            // ReduceWordDetectCreateRef peak pool
+	Takes   [][][]int    `json:"takes,omitempty"`    // per take Reduced, match.EnrollParams.KeepTakes
+	PoolAvg [][]int      `json:"pool_avg,omitempty"` // ReduceWordDetectCreateRef avg pool, optional
+}
+
+// New returns the Template of 'ref' built with 'prm'; 'poolAvg' may be nil
+func New(prm store.Params, ref match.RefWord, poolAvg [][]int) Template {
+	return Template{Params: prm, Label: ref.Label, Reduced: ref.Reduced, Takes: ref.Takes, PoolAvg: poolAvg}
+}
+
+// RefWord returns 't' as the reference word match.Detect and match.ReduceWordDetectN take
+func (t Template) RefWord() match.RefWord {
+	return match.RefWord{Label: t.Label, Reduced: t.Reduced, Takes: t.Takes}
+}
+
+// Encode returns 't' as a template file, header and payload
+func Encode(t Template) ([]byte, error) {
+	p := make([]byte, 0, 256)
+	p = store.EncodeParams(p, t.Params)
+	p, err := store.EncodeRefWord(p, t.RefWord())
+	if err != nil {
+		return nil, err
+	}
+	flags := uint16(0)
+	if t.PoolAvg != nil {
+		flags |= flagPoolAvg
+		if p, err = store.EncodeMatrix(p, t.PoolAvg); err != nil {
+			return nil, fmt.Errorf("template: PoolAvg: %v", err)
+		}
+	}
+	b := make([]byte, headerSize, headerSize+len(p))
+	copy(b[0:4], Magic)
+	binary.LittleEndian.PutUint16(b[4:6], Version)
+	binary.LittleEndian.PutUint16(b[6:8], flags)
+	binary.LittleEndian.PutUint32(b[8:12], uint32(len(p)))
+	binary.LittleEndian.PutUint32(b[12:16], crc32.ChecksumIEEE(p))
+	return append(b, p...), nil
+} // end func Encode
+
+// Decode returns the Template in 'b', a template file written by Encode
+func Decode(b []byte) (t Template, err error) {
+	if len(b) < headerSize || string(b[0:4]) != Magic {
+		return t, ErrFormat
+	}
+	version := binary.LittleEndian.Uint16(b[4:6])
+	if version < 1 || version > Version {
+		return t, ErrVersion
+	}
+	flags := binary.LittleEndian.Uint16(b[6:8])
+	n := int(binary.LittleEndian.Uint32(b[8:12]))
+	if n > len(b)-headerSize {
+		return t, ErrCorrupt
+	}
+	payload := b[headerSize : headerSize+n]
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(b[12:16]) {
+		return t, ErrChecksum
+	}
+	d := store.NewDecoder(payload)
+	t.Params = d.ParamsVersion(version)
+	ref := d.RefWord()
+	t.Label, t.Reduced, t.Takes = ref.Label, ref.Reduced, ref.Takes
+	if flags&flagPoolAvg != 0 {
+		t.PoolAvg = d.Matrix()
+	}
+	if d.Err() != nil || d.Len() != 0 {
+		return Template{}, ErrCorrupt
+	}
+	return t, nil
+} // end func Decode
+
+// ReadFile reads template file 'filename'
+func ReadFile(filename string) (Template, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return Template{}, err
+	}
+	return Decode(b)
+}
+
+// WriteFile writes 't' to template file 'filename'
+func WriteFile(filename string, t Template) error {
+	b, err := Encode(t)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0644)
+}
+
+// WriteJSON writes 't' to 'w' as indented json
+func WriteJSON(w io.Writer, t Template) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+// ReadJSON reads a Template written by WriteJSON from 'r'
+func ReadJSON(r io.Reader) (t Template, err error) {
+	err = json.NewDecoder(r).Decode(&t)
+	return t, err
+}
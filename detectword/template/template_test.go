@@ -0,0 +1,154 @@
+// @file TinyGo/detectword/template/template_test.go
+// @date 2026.10.18
+// @info Encode to Decode round trip of template files, old versions and the error paths
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package template
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"localhost/detectword/match"
+	"localhost/detectword/store"
+)
+
+var testParams = store.Params{Tbins: 32, Fbins: 64, BufSize: 8192, SleepTime: 125, VBlocks: 4,
+	HBlocks: 4, VBlocks2: 2, HBlocks2: 2, SpectThresh: 60, FrameLen: 512, Spect: 3, MelFilters: 20,
+	MelLowHz: 100, MelHighHz: 3800, MFCCs: 12, Lifter: 22, Deltas: 1}
+
+func testTemplate() Template {
+	ref := match.RefWord{Label: "lights on",
+		Reduced: [][]int{{1, -2, 3}, {1 << 20, 0, -(1 << 30)}},
+		Takes:   [][][]int{{{1, 2, 3}, {4, 5, 6}}, {{-1, -2, -3}, {0, 0, 0}}}}
+	return New(testParams, ref, [][]int{{7, 8}, {9, 10}, {11, 12}})
+}
+
+func TestRoundTrip(t *testing.T) {
+	full := testTemplate()
+	noTakes := full
+	noTakes.Takes, noTakes.PoolAvg = nil, nil
+	for _, want := range []Template{full, noTakes} {
+		b, err := Encode(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Decode(Encode(t)) = %+v, want %+v", got, want)
+		}
+		if !reflect.DeepEqual(got.RefWord(), match.RefWord{Label: want.Label, Reduced: want.Reduced,
+			Takes: want.Takes}) {
+			t.Errorf("RefWord = %+v", got.RefWord())
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	want := testTemplate()
+	name := filepath.Join(t.TempDir(), "on"+Ext)
+	if err := WriteFile(name, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadFile(name); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadFile = %+v, %v, want %+v", got, err, want)
+	}
+
+	var b bytes.Buffer
+	if err := WriteJSON(&b, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ReadJSON(&b); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadJSON = %+v, %v, want %+v", got, err, want)
+	}
+}
+
+// file returns a template file of 'version' with 'payload'
+func file(version, flags uint16, payload []byte) []byte {
+	b := make([]byte, headerSize, headerSize+len(payload))
+	copy(b, Magic)
+	binary.LittleEndian.PutUint16(b[4:6], version)
+	binary.LittleEndian.PutUint16(b[6:8], flags)
+	binary.LittleEndian.PutUint32(b[8:12], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(b[12:16], crc32.ChecksumIEEE(payload))
+	return append(b, payload...)
+}
+
+func TestOldVersions(t *testing.T) {
+	tmpl := testTemplate()
+	tmpl.PoolAvg = nil
+	params := store.EncodeParams(nil, tmpl.Params)
+	ref, err := store.EncodeRefWord(nil, tmpl.RefWord())
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1 := store.Params{Tbins: 32, Fbins: 64, BufSize: 8192, SleepTime: 125, VBlocks: 4, HBlocks: 4,
+		VBlocks2: 2, HBlocks2: 2, SpectThresh: 60}
+	v2 := v1
+	v2.FrameLen = 512
+	v3 := v2
+	v3.Spect = 3
+	tests := []struct {
+		version uint16
+		nParams int // uint16 Params the version wrote
+		want    store.Params
+	}{{1, 9, v1}, {2, 10, v2}, {3, 11, v3}, {4, 17, testParams}}
+	for _, tt := range tests {
+		payload := append(append([]byte(nil), params[:2*tt.nParams]...), ref...)
+		got, err := Decode(file(tt.version, 0, payload))
+		if err != nil {
+			t.Fatalf("version %d: %v", tt.version, err)
+		}
+		want := tmpl
+		want.Params = tt.want
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("version %d: Decode = %+v, want %+v", tt.version, got.Params, want.Params)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	good, err := Encode(testTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := good[headerSize:]
+	edit := func(f func(b []byte)) []byte {
+		b := append([]byte(nil), good...)
+		f(b)
+		return b
+	}
+	tests := []struct {
+		name string
+		b    []byte
+		want error
+	}{
+		{"empty", nil, ErrFormat},
+		{"short header", good[:headerSize-1], ErrFormat},
+		{"bad magic", edit(func(b []byte) { b[0] = 'X' }), ErrFormat},
+		{"version 0", edit(func(b []byte) { b[4] = 0 }), ErrVersion},
+		{"newer version", edit(func(b []byte) { b[4] = Version + 1 }), ErrVersion},
+		{"length past end", edit(func(b []byte) { b[8]++ }), ErrCorrupt},
+		{"truncated", good[:len(good)-1], ErrCorrupt},
+		{"payload bit", edit(func(b []byte) { b[headerSize+5] ^= 1 }), ErrChecksum},
+		{"pool avg flag missing", file(Version, 0, payload), ErrCorrupt},
+		{"pool avg flag without matrix", file(Version, flagPoolAvg, payload[:len(payload)-2-6*4]), ErrCorrupt},
+	}
+	for _, tt := range tests {
+		if _, err := Decode(tt.b); err != tt.want {
+			t.Errorf("%s: Decode = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
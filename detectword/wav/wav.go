@@ -0,0 +1,234 @@
+// @file TinyGo/detectword/wav/wav.go
+// @date 2026.10.18
+// @info read 16 bit pcm wav files, e.g. laptop recordings of reference and test words
+// @date 2026.10.18 added Write(), and Adc2Pcm()/Pcm2Adc() for []uint16 adc captures
+// @date 2026.10.18 Read grows samples as read, not from the declared chunk sizes
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: host only; go build
+
+package wav
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	formatPCM        = 1
+	formatExtensible = 0xFFFE
+)
+
+// AdcMid is the dc offset of a mid scale adc sample; the mic amplifier is biased at 3.3V/2
+const AdcMid = 0x8000
+
+// AdcMask clears the 4 low bits of a 16 bit sample; the pico adc is 12 bits, left justified
+const AdcMask = 0xFFF0
+
+// maxPrealloc caps the samples Read allocates ahead of reading them, a minute at 16kHz
+const maxPrealloc = 60 * 16000
+
+// ErrFormat is returned for files which are not 16 bit pcm wav
+var ErrFormat = errors.New("wav: not a 16 bit pcm wav file")
+
+// Read reads a 16 bit pcm wav stream from 'r' and returns its samples, mixed
+// down to mono, and the header sample rate in Hz
+func Read(r io.Reader) (samples []int16, sampleRate int, err error) {
+	br := bufio.NewReader(r)
+	var riff [12]byte
+	if _, err = io.ReadFull(br, riff[:]); err != nil {
+		return nil, 0, err
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, 0, ErrFormat
+	}
+	channels := 0
+	for { // walk chunks until 'data'; 'fmt ' must precede it
+		var hdr [8]byte
+		if _, err = io.ReadFull(br, hdr[:]); err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("wav: no data chunk")
+			}
+			return nil, 0, err
+		}
+		id := string(hdr[0:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, 0, ErrFormat
+			}
+			var chunk [16]byte // the pcm fields; any extension is skipped
+			if _, err = io.ReadFull(br, chunk[:]); err != nil {
+				return nil, 0, err
+			}
+			if _, err = br.Discard(int(size - 16)); err != nil {
+				return nil, 0, err
+			}
+			format := binary.LittleEndian.Uint16(chunk[0:2])
+			channels = int(binary.LittleEndian.Uint16(chunk[2:4]))
+			sampleRate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+			bits := binary.LittleEndian.Uint16(chunk[14:16])
+			if (format != formatPCM && format != formatExtensible) || bits != 16 || channels < 1 {
+				return nil, 0, ErrFormat
+			}
+		case "data":
+			if channels == 0 {
+				return nil, 0, fmt.Errorf("wav: data chunk before fmt chunk")
+			}
+			// samples grow as read; the declared size only caps them, and a streamed
+			// recording may declare 0xFFFFFFFF
+			frames := size / int64(2*channels)
+			samples = make([]int16, 0, minInt64(frames, maxPrealloc))
+			frame := make([]byte, 2*channels)
+			for i := int64(0); i < frames; i++ {
+				if _, err = io.ReadFull(br, frame); err != nil {
+					return samples, sampleRate, nil // truncated recording; keep what was read
+				}
+				sum := 0
+				for c := 0; c < channels; c++ {
+					sum += int(int16(binary.LittleEndian.Uint16(frame[2*c:])))
+				}
+				samples = append(samples, int16(sum/channels))
+			}
+			return samples, sampleRate, nil
+		default: // skip LIST, fact, etc; chunks are padded to even size
+			if _, err = br.Discard(int(size + size%2)); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		if size%2 == 1 {
+			br.Discard(1)
+		}
+	} // end walk chunks
+} // end func Read
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// ReadFile is Read on file 'filename'
+func ReadFile(filename string) (samples []int16, sampleRate int, err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+	samples, sampleRate, err = Read(file)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %v", filename, err)
+	}
+	return samples, sampleRate, nil
+}
+
+// Write writes mono 'samples' at 'sampleRate' Hz to 'w' as a 16 bit pcm wav stream
+func Write(w io.Writer, samples []int16, sampleRate int) error {
+	dataSize := uint32(2 * len(samples))
+	hdr := make([]byte, 44)
+	copy(hdr[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(hdr[4:8], 36+dataSize)
+	copy(hdr[8:16], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(hdr[16:20], 16)                   // fmt chunk size
+	binary.LittleEndian.PutUint16(hdr[20:22], formatPCM)            // format
+	binary.LittleEndian.PutUint16(hdr[22:24], 1)                    // channels
+	binary.LittleEndian.PutUint32(hdr[24:28], uint32(sampleRate))   // sample rate
+	binary.LittleEndian.PutUint32(hdr[28:32], uint32(2*sampleRate)) // byte rate
+	binary.LittleEndian.PutUint16(hdr[32:34], 2)                    // block align
+	binary.LittleEndian.PutUint16(hdr[34:36], 16)                   // bits per sample
+	copy(hdr[36:40], "data")
+	binary.LittleEndian.PutUint32(hdr[40:44], dataSize)
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(hdr); err != nil {
+		return err
+	}
+	var b [2]byte
+	for _, v := range samples {
+		binary.LittleEndian.PutUint16(b[:], uint16(v))
+		if _, err := bw.Write(b[:]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+} // end func Write
+
+// WriteFile is Write to file 'filename'
+func WriteFile(filename string, samples []int16, sampleRate int) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err = Write(file, samples, sampleRate); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Adc2Pcm converts adc samples to signed pcm by removing dc offset 'dc', e.g. AdcMid
+// or AdcDC(u). With 'dc' AdcMid the conversion is lossless and Pcm2Adc reverses it.
+func Adc2Pcm(u []uint16, dc uint16) []int16 {
+	pcm := make([]int16, len(u))
+	for i, v := range u {
+		pcm[i] = int16(clip16(int(v) - int(dc)))
+	}
+	return pcm
+}
+
+// Pcm2Adc converts signed pcm to adc samples; adds dc offset 'dc' and truncates to 12 bit
+// resolution left justified in 16 bits, as returned by machine.ADC.Get()
+func Pcm2Adc(pcm []int16, dc uint16) []uint16 {
+	u := make([]uint16, len(pcm))
+	for i, v := range pcm {
+		u[i] = uint16(clip16(int(v)+int(dc)-AdcMid)+AdcMid) & AdcMask
+	}
+	return u
+}
+
+// AdcDC returns the average, i.e. the dc offset, of adc samples 'u'
+func AdcDC(u []uint16) uint16 {
+	if len(u) == 0 {
+		return AdcMid
+	}
+	sum := 0
+	for _, v := range u {
+		sum += int(v)
+	}
+	return uint16(sum / len(u))
+}
+
+// ReadAdcFile reads wav 'filename' as adc samples with dc offset AdcMid
+func ReadAdcFile(filename string) (u []uint16, sampleRate int, err error) {
+	pcm, sampleRate, err := ReadFile(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	return Pcm2Adc(pcm, AdcMid), sampleRate, nil
+}
+
+// WriteAdcFile writes adc samples 'u', captured at 'sampleRate' Hz, to wav 'filename';
+// samples are written relative to AdcMid so ReadAdcFile returns them unchanged
+func WriteAdcFile(filename string, u []uint16, sampleRate int) error {
+	return WriteFile(filename, Adc2Pcm(u, AdcMid), sampleRate)
+}
+
+// clip16 limits 'v' to the int16 range
+func clip16(v int) int {
+	if v < -32768 {
+		return -32768
+	}
+	if v > 32767 {
+		return 32767
+	}
+	return v
+}
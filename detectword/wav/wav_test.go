@@ -0,0 +1,176 @@
+// @file TinyGo/detectword/wav/wav_test.go
+// @date 2026.10.18
+// @info wav Write/Read and adc file round trips, stereo mixdown, chunk skipping, and
+//       declared chunk sizes past the stream
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @build: go test
+
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// chunk returns a riff chunk 'id' holding 'data', padded to even size
+func chunk(id string, data []byte) []byte {
+	b := make([]byte, 8, 8+len(data)+1)
+	copy(b, id)
+	binary.LittleEndian.PutUint32(b[4:], uint32(len(data)))
+	b = append(b, data...)
+	if len(data)%2 == 1 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// fmtChunk returns a 16 bit pcm fmt chunk
+func fmtChunk(channels, sampleRate int) []byte {
+	d := make([]byte, 16)
+	binary.LittleEndian.PutUint16(d[0:], formatPCM)
+	binary.LittleEndian.PutUint16(d[2:], uint16(channels))
+	binary.LittleEndian.PutUint32(d[4:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(d[8:], uint32(2*channels*sampleRate))
+	binary.LittleEndian.PutUint16(d[12:], uint16(2*channels))
+	binary.LittleEndian.PutUint16(d[14:], 16)
+	return chunk("fmt ", d)
+}
+
+// dataChunk returns a data chunk of interleaved 'samples'
+func dataChunk(samples ...int16) []byte {
+	d := make([]byte, 2*len(samples))
+	for i, v := range samples {
+		binary.LittleEndian.PutUint16(d[2*i:], uint16(v))
+	}
+	return chunk("data", d)
+}
+
+// riff returns a wav stream of 'chunks'
+func riff(chunks ...[]byte) []byte {
+	body := []byte("WAVE")
+	for _, c := range chunks {
+		body = append(body, c...)
+	}
+	return chunk("RIFF", body)
+}
+
+func TestWriteRead(t *testing.T) {
+	samples := []int16{0, 1, -1, 32767, -32768, 1234, -4321}
+	var b bytes.Buffer
+	if err := Write(&b, samples, 22050); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 44+2*len(samples) {
+		t.Errorf("wrote %d bytes, want %d", b.Len(), 44+2*len(samples))
+	}
+	got, rate, err := Read(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 22050 || !reflect.DeepEqual(got, samples) {
+		t.Errorf("Read = %v at %d Hz, want %v at 22050 Hz", got, rate, samples)
+	}
+}
+
+func TestAdcFileRoundTrip(t *testing.T) {
+	u := []uint16{0x8000, 0x0000, 0xFFF0, 0x7FF0, 0x8010, 0x1230}
+	name := filepath.Join(t.TempDir(), "cap.wav")
+	if err := WriteAdcFile(name, u, 3164); err != nil {
+		t.Fatal(err)
+	}
+	got, rate, err := ReadAdcFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 3164 || !reflect.DeepEqual(got, u) {
+		t.Errorf("ReadAdcFile = %#x at %d Hz, want %#x at 3164 Hz", got, rate, u)
+	}
+	// the 4 bits below the 12 bit adc are not kept
+	if got := Pcm2Adc([]int16{0x0F, -1}, AdcMid); !reflect.DeepEqual(got, []uint16{0x8000, 0x7FF0}) {
+		t.Errorf("Pcm2Adc low bits = %#x, want [0x8000 0x7ff0]", got)
+	}
+}
+
+func TestReadStereo(t *testing.T) {
+	// left, right frames; the mixdown averages them, truncating toward 0
+	w := riff(fmtChunk(2, 8000), dataChunk(100, 200, -100, -300, 32767, 32767, 1, 2))
+	got, rate, err := Read(bytes.NewReader(w))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int16{150, -200, 32767, 1}; rate != 8000 || !reflect.DeepEqual(got, want) {
+		t.Errorf("Read = %v at %d Hz, want %v at 8000 Hz", got, rate, want)
+	}
+}
+
+func TestReadSkipsChunks(t *testing.T) {
+	// odd sized chunks carry a pad byte, before and after fmt
+	w := riff(chunk("LIST", []byte("INFOabc")), fmtChunk(1, 16000), chunk("fact", []byte{1, 2, 3}),
+		dataChunk(7, -7, 9))
+	got, rate, err := Read(bytes.NewReader(w))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int16{7, -7, 9}; rate != 16000 || !reflect.DeepEqual(got, want) {
+		t.Errorf("Read = %v at %d Hz, want %v at 16000 Hz", got, rate, want)
+	}
+}
+
+func TestReadDeclaredSize(t *testing.T) {
+	// a data chunk declaring more than it holds; 0xFFFFFFFF is a streamed recording
+	for _, size := range []uint32{10, 0xFFFFFFFF} {
+		data := dataChunk(7, -7, 9)
+		binary.LittleEndian.PutUint32(data[4:], size)
+		w := riff(fmtChunk(1, 16000), data)
+		got, _, err := Read(bytes.NewReader(w))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []int16{7, -7, 9}; !reflect.DeepEqual(got, want) {
+			t.Errorf("size %#x: Read = %v, want %v", size, got, want)
+		}
+		if cap(got) > maxPrealloc {
+			t.Errorf("size %#x: %d samples allocated, max %d", size, cap(got), maxPrealloc)
+		}
+	}
+
+	// a fmt chunk with an extension is read to its declared end; one declaring 4GB is not
+	ext := fmtChunk(1, 8000)
+	ext = chunk("fmt ", append(ext[8:], 0, 0))
+	got, rate, err := Read(bytes.NewReader(riff(ext, dataChunk(5))))
+	if err != nil || rate != 8000 || !reflect.DeepEqual(got, []int16{5}) {
+		t.Errorf("fmt extension: Read = %v at %d Hz, %v, want [5] at 8000 Hz", got, rate, err)
+	}
+	huge := fmtChunk(1, 8000)
+	binary.LittleEndian.PutUint32(huge[4:], 0xFFFFFFFF)
+	if _, _, err = Read(bytes.NewReader(riff(huge, dataChunk(5)))); err == nil {
+		t.Error("fmt chunk past the stream: no error")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	eightBit := fmtChunk(1, 8000)
+	binary.LittleEndian.PutUint16(eightBit[8+14:], 8)
+	tests := []struct {
+		name string
+		w    []byte
+	}{
+		{"not riff", []byte("RIFX\x00\x00\x00\x00WAVE")},
+		{"8 bit", riff(eightBit, dataChunk(1))},
+		{"data before fmt", riff(dataChunk(1), fmtChunk(1, 8000))},
+		{"no data", riff(fmtChunk(1, 8000))},
+		{"short", []byte("RIFF")},
+	}
+	for _, tt := range tests {
+		if _, _, err := Read(bytes.NewReader(tt.w)); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
@@ -31,6 +31,37 @@
 // @date 2022.04.13 commented Print*, capture_diags false for --prod--
 //                  --prod-- Tbins/Fbins to 32 from 64, SpectThresh to 60 from 50
 // @date 2022.04.18 removed import 'common'; const Tag* added locally
+// @date 2026.10.18 dsp and matching moved to localhost/detectword/dsp and /match; this file is the
+//                  firmware entry point only
+// @date 2026.10.18 wordLabels reference words, up to match.MaxRefWords, detected with ReduceWordDetectN;
+//                  words 0 and 1 set gpio10 high and low, other words are detected and leave gpio10
+// @date 2026.10.18 match.Detect with DefaultDetectParams; only a match.Match outcome changes gpio10
+// @date 2026.10.18 enrollParams.Takes takes per reference word, combined with match.EnrollWord; led flashes
+//                  once per take, three times when takes disagree and the word must be repeated
+// @date 2026.10.18 enrolled references saved to machine.Flash with store.Save after training, and loaded
+//                  at power on when built with the current params, skipping training; gpio15 to gnd at
+//                  power on forces training
+// @date 2026.10.18 adc.Cap2Uint16Pre; captures keep pre_trigger_ms before the threshold sample
+// @date 2026.10.18 adc.Cap2VAD; vadParams frame energy VAD starts and ends captures
+// @date 2026.10.18 noiseFloor tracks ambient noise between utterances; VAD and noise rejection thresholds
+//                  follow it, and capture_diags outputs the estimate with noiseDiags, file00_noise.dat
+// @date 2026.10.18 params moved to cfg, a config.Config validated at power on, in place of main() locals;
+//                  an invalid cfg flashes the led 7 times, repeated, in place of the power of 2 panic
+// @date 2026.10.18 console commands over machine.Serial, polled between captures and interrupting the
+//                  wait for speech with adc.Cap2VADSerial; set applies a new cfg, enroll repeats training
+//                  of a word, diags replaces capture_diags, output forces gpio10
+// @date 2026.10.18 captureDiags announces cfg in file00_params.dat first, for the dwuart shape check
+// @date 2026.10.18 console 'diags binary'; frameDiags sends detectword/frame frames in place of the text,
+//                  and a detection frame per detection
+// @date 2026.10.18 cfg.Clocked captures with adc.SerialFifoSampler, clocked by the adc at an exact rate, in
+//                  place of sleep pacing; console state reports the achieved rate and fifo overruns
+// @date 2026.10.18 references stored to refStore(), machine.Flash only when built with '-tags flash' on a
+//                  TinyGo providing it; the TinyGo v0.21 default build trains at every power on
+// @date 2026.10.18 machine.Flash reference store built by default; '-tags noflash' for TinyGo v0.21
+// @date 2026.10.18 config.Default() is the pre 2026 --prod--: 1 take, fixed noise thresholds; takes,
+//                  adaptive noise floor, min confidence and max err are --dev-- settings
+// @date 2026.10.18 file00_params.dat lines are frame.ParamNames, as a TypeParams frame; adds FrameLen,
+//                  Hop, Spect and the mel front end
 
 package main
 
@@ -39,6 +70,12 @@ import (
 	"time"
 	// "runtime" // runtime.GC is disabled
 	"localhost/adc"     // underscore disable for --no mic-- mode
+	"localhost/detectword/config"
+	"localhost/detectword/console"
+	"localhost/detectword/dsp"
+	"localhost/detectword/frame"
+	"localhost/detectword/match"
+	"localhost/detectword/store"
 	"machine"
 )
 
@@ -48,53 +85,151 @@ const Tag_eod      = "--eod--" // end of data
 const Tag_eot      = "--eot--" // end of transmission
 const Out_file     = "not-in-git.txt" // scratch file, e.g. created by dsp.Pull()
 
-// Acquire first 2 data sets (words) as references for subsequent captures;  e.g. "on" and "off"
+// Acquire first len(wordLabels) data sets (words) as references for subsequent captures;  e.g. "on" and "off"
 func main() {
 	// --quiet-- fmt.Printf("\n\r## detectword_pico %s\n\r", fmt.Sprintf("%s",time.Now())[:16])
 	time.Sleep(time.Millisecond * 1000) // power stabalize; added 20220401; usb batt #1 producing connect bounce
 	
-	// adc and spectrograph parameters
-	Tbins := 64 // --prod-- 64
-	Fbins := 64 // --prod-- 64
-	buf_size := 1024  // --prod-- 1024 
-	sleep_time := 250 // --prod-- 250; 'sleep_time'us + 16us == 'adc.Get' time; 'Tsamp' in octave  mfiles
-	SpectThresh := uint16(50)  // --prod-- 50; ignore spect array elements below SpectThresh
-	MinWordLen := int(0.2 * float64(buf_size)) // don't process sounds less than X% of buf_sizes
-	// Reduction params
-	vBlocks  := 8;  hBlocks := 8  // reduction block size for avg pool; require power of 2
-	vBlocks2 := 4; hBlocks2 := 4  // reduction block size for peak pool; require power of 2
+	// adc, spectrograph, reduction, detection and enrollment parameters; see config.Default()
+	// --prod-- Tbins 64, Fbins 64, BufSize 1024, SleepTime 250 ('SleepTime'us + 16us == 'adc.Get' time;
+	// 'Tsamp' in octave mfiles), PreTriggerMs 20, SpectThresh 50, MinWordLen 0.2 BufSize, avg pool
+	// VBlocks/HBlocks 8/8, peak pool VBlocks2/HBlocks2 4/4, noise margin 400 (the pre 2026 -400 <
+	// lse-dse < 400 band), no min confidence or max err, 1 take per word, fixed start/stop energy
+	// 4900/900 and noise threshold 0xBFFF
+	cfg := config.Default()
+	// --dev-- cfg.Metric = int(match.DTWMetric); cfg.DTWBand = 1 // tolerate speaking rate
+	// --dev-- cfg.AdaptNoise = true // VAD and noise thresholds follow the ambient noise floor
+	// --dev-- cfg.Takes, cfg.MinAccepted = 3, 2 // takes averaged, a disagreeing take rejected
+	// --dev-- cfg.MinConfidence, cfg.MaxErr = 0.05, 1600
+	vadParams := cfg.VAD // 8.5 ms frames, 100 ms hangover, 25 ms min speech
+	noiseFloor := adc.NewNoiseFloor(cfg.Noise) // start/stop 12/6 dB over ambient
+	if cfg.AdaptNoise {
+		vadParams.Noise = noiseFloor
+	}
 	LightState := false // off/on = false/true
 	_ = LightState // --dev-- set to track gpio output state, and otherwise currently unused
-	capture_diags := false  // --dev-- diagnostics mode; acquiare pico outputs from raspi
+	wordLabels := []string{"light", "dark"} // --prod-- 2; reference words in training order, up to match.MaxRefWords
+	nWords := len(wordLabels)
+	enrollParams := match.EnrollParamsOf(cfg)
+
+	// console; line commands over usb uart, e.g. 'set NoiseMargin 50', 'enroll dark', 'help'
+	st := console.NewState(cfg, wordLabels)
+	st.Diags = false // --dev-- diagnostics mode, formerly capture_diags; acquiare pico outputs from raspi
+	st.Noise = vadParams.Noise
+	con := console.New(st, machine.Serial)
+	con.EOL = "\n\r"
+	enc := frame.NewEncoder(machine.Serial) // 'diags binary' output
 
 	// gpio config
 	gpio10 := machine.GP10 // physical pin 14, physical pin 13 == gnd
 	led := machine.LED
 	gpio10.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	led.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	gpio15 := machine.GP15 // physical pin 20; jumper to gnd at power on to force training
+	gpio15.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+
+	if err := cfg.Validate(); err != nil { // e.g. BufSize/Tbins not a power of 2
+		for { // --ever-- nothing works with this cfg
+			flashCount(led, 7)
+			time.Sleep(time.Millisecond * 1000)
+		}
+	}
 
 	// initialize iSpectRefReduced* and Create* loop memory
-	fftPoints := buf_size/Tbins // e.g. for 1024 with 64 Tbins: (/ 1024 64) 16 points per fft; require power of 2
-	if IsPow2(fftPoints) != true {
-		panic("fft() requires power of 2 input size" + GetFunctionName(CreateU16SpectFromU16))
+	ref_init := make([]uint16, cfg.BufSize) // for allocation sizing only
+	HammingFftPoints := dsp.Hamming(cfg.FftPoints()) // e.g. for 1024 with 64 Tbins: (/ 1024 64) 16 points per fft
+	U16SpectRef, _ := dsp.CreateU16SpectConfig( ref_init, HammingFftPoints, cfg, dsp.NoiseThreshold )
+	refs := make([]match.RefWord, nWords)
+	for i,_ := range refs {
+		refs[i].Label = wordLabels[i]
+		refs[i].Reduced, _ = match.ReduceConfig( U16SpectRef, cfg )
 	}
-	ref_init := make([]uint16, buf_size) // for allocation sizing only
-	HammingFftPoints := Hamming(fftPoints) 
-	U16SpectRef, _ := CreateU16SpectFromU16 ( ref_init, HammingFftPoints, Tbins, Fbins, buf_size, SpectThresh )
-	iSpectRefReducedLight, iSpectRefReducedLight_PoolAvg := ReduceWordDetectCreateRef( U16SpectRef, Fbins, Tbins,
-		vBlocks, hBlocks, vBlocks2, hBlocks2 )
-	iSpectRefReducedDark, _  := ReduceWordDetectCreateRef( U16SpectRef, Fbins, Tbins,
-		vBlocks, hBlocks, vBlocks2, hBlocks2 )	
 	ref_init = nil
+
+	// stored references are only valid for the params they were built with
+	storeParams := store.ParamsOf(cfg)
 	
-	// fmt.Printf("First 2 sounds set 'light' and 'dark' ref\n\r")
-	loopCt := 0 
+	// samplers; sleep paced, or with cfg.Clocked the adc free running at cfg.SampleRate(); both
+	// give up waiting for speech on console input
+	paced := &adc.SerialSampler{PicoSampler: adc.PicoSampler{Pin: machine.ADC0}}
+	clocked := &adc.SerialFifoSampler{FifoSampler: adc.FifoSampler{Pin: machine.ADC0}}
+
+	// fmt.Printf("First nWords x enrollParams.Takes sounds set 'light' and 'dark' ref\n\r")
+	loopCt := 0 // index of the word being enrolled; nWords when training is complete
+	only := false // console 'enroll word'; only loopCt is enrolled, the words after it are kept
+	takes := make([][][]int, 0, enrollParams.Takes)
+	flash := refStore() // nil with '-tags noflash'; references are not kept across power off
+	if flash != nil && gpio15.Get() { // no training jumper; load references enrolled before power off
+		rec, err := store.Load(flash)
+		if err == nil && rec.Matches(storeParams, wordLabels) {
+			refs = rec.Refs
+			loopCt = nWords // training complete
+			flashOn(gpio10); flashOff(gpio10) // gpio10 flash twice signifies references loaded
+		}
+	}
 	for { // --ever--
-		
+
+		// console commands run here, between captures; serial input interrupts the wait for speech
+		st.Refs, st.Word, st.Only, st.Takes, st.Light = refs, loopCt, only, len(takes), LightState
+		con.Poll(machine.Serial)
+		if st.Reconfig { // console 'set'; st.Cfg is validated
+			retrain := store.ParamsOf(st.Cfg) != storeParams
+			cfg = st.Cfg
+			st.Reconfig = false
+			vadParams = cfg.VAD
+			noiseFloor.P = cfg.Noise // the estimate is kept
+			if cfg.AdaptNoise {
+				vadParams.Noise = noiseFloor
+			}
+			st.Noise = vadParams.Noise
+			enrollParams = match.EnrollParamsOf(cfg)
+			HammingFftPoints = dsp.Hamming(cfg.FftPoints())
+			storeParams = store.ParamsOf(cfg)
+			if retrain { // references no longer match the spectrogram and reduction; train all words
+				loopCt, only = 0, false
+				takes = takes[:0]
+			}
+		}
+		if st.Enroll != console.NoEnroll { // console 'enroll'
+			loopCt, only = st.Enroll, !st.EnrollAll
+			takes = takes[:0]
+			st.Enroll = console.NoEnroll
+		}
+		switch st.Output { // console 'output'; OutputAuto leaves gpio10 to detections
+		case console.OutputOn:
+			gpio10.High()
+			LightState = true
+		case console.OutputOff:
+			gpio10.Low()
+			LightState = false
+		}
+
 		// --quiet-- fmt.Printf("Waiting for sound...") 
 		// --quiet-- fmt.Printf("sound...") 
-		uBuf := adc.Cap2Uint16(buf_size, sleep_time)
-		if len(uBuf) < MinWordLen {
+		var sampler adc.Sampler = paced
+		if cfg.Clocked {
+			clocked.SetRate(cfg.SampleRate())
+			sampler = clocked
+		}
+		uBuf, seg := adc.CaptureVAD(sampler, led, cfg.BufSize, cfg.SleepTime, cfg.PreTriggerMs, vadParams)
+		if seg.Reason == adc.VADNone { // interrupted by console input
+			continue
+		}
+		st.Captures++
+		st.Rate = sampler.(adc.RateMeter).Rate()
+		if cfg.Clocked {
+			st.Overruns += clocked.Overruns()
+		}
+		noiseThresh := uint16(dsp.NoiseThreshold) // capture peak below is noise
+		if vadParams.Noise != nil {
+			noiseThresh = noiseFloor.PeakThreshold(vadParams.DC)
+		}
+		if st.Diags && st.Binary {
+			enc.Noise( noiseFloor, seg, noiseThresh )
+		} else if st.Diags {
+			noiseDiags( noiseFloor, seg, noiseThresh )
+		}
+		if len(uBuf) < cfg.MinWordLen {
 			flashOn(led); flashOn(led)
 			continue
 		}
@@ -102,41 +237,68 @@ func main() {
 		// fmt.Println("--debug-- len(uBuf):", len(uBuf))
 		// fmt.Printf("--debug-- uBuf:\n\r") // capture raw samples with minicom
 
-		// process first two captures as ref words
-		if loopCt < 2 {
-			// verify time domain uBuf is not noise; 0xBFFF is 0.75 0xFFFF
-			_, bIsNoise := NormalizeU16_ac_threshold(uBuf, 0xBFFF)
-			if bIsNoise { // don't process and repeat this loop pass
+		// process first nWords x enrollParams.Takes captures as ref word takes
+		if loopCt < nWords {
+			// create new take of ref loopCt, e.g. 'light' then 'dark'; noise checked
+			// with NormalizeU16_ac_threshold and CreateU16SpectConfig
+			iSpectRefReduced, iSpectRefReduced_PoolAvg, U16SpectRef, bIsNoise := match.EnrollTake( uBuf,
+				HammingFftPoints, noiseThresh, enrollParams )
+			if bIsNoise { // don't process and repeat this take
 				flashOn(led)
 				continue
 			}
-			
-			if loopCt == 0 {
-				// create new light ref from initial capture
-				U16SpectRef, bIsNoise = CreateU16SpectFromU16 ( uBuf, HammingFftPoints,
-					Tbins, Fbins, buf_size, SpectThresh )
-				iSpectRefReducedLight, iSpectRefReducedLight_PoolAvg =
-					ReduceWordDetectCreateRef( U16SpectRef, Fbins, Tbins,
-						vBlocks, hBlocks, vBlocks2, hBlocks2 )
-
-				if capture_diags { // raspi diagnostics acquisition
-					// create --uart out-- files for *_xt.dat, *_spect.dat, *_pool1/2.dat
-					// '--' tagging embedded in uartHeader(); requires --eod-- to close file write	
-					captureDiags( uBuf, U16SpectRef, iSpectRefReducedLight_PoolAvg, iSpectRefReducedLight)
-				} // end if capture_diags 
+			if st.Diags && loopCt == 0 && len(takes) == 0 { // raspi diagnostics acquisition
+				// create --uart out-- files for *_xt.dat, *_spect.dat, *_pool1/2.dat
+				// '--' tagging embedded in uartHeader(); requires --eod-- to close file write	
+				if st.Binary {
+					frameDiags( enc, cfg, uBuf, U16SpectRef, iSpectRefReduced_PoolAvg, iSpectRefReduced,
+						noiseFloor, seg, noiseThresh )
+				} else {
+					captureDiags( cfg, uBuf, U16SpectRef, iSpectRefReduced_PoolAvg, iSpectRefReduced,
+						noiseFloor, seg, noiseThresh )
+				}
+			} // end if capture_diags 
+			takes = append(takes, iSpectRefReduced)
+			if len(takes) < enrollParams.Takes { // led flash signifies take accepted, say it again
+				flashCount(led, 1)
+				continue
 			}
-			if loopCt == 1 {
-				// create new dark ref from initial capture
-				U16SpectRef, bIsNoise = CreateU16SpectFromU16 ( uBuf, HammingFftPoints,
-					Tbins, Fbins, buf_size, SpectThresh )
-				iSpectRefReducedDark, _  = ReduceWordDetectCreateRef( U16SpectRef, Fbins, Tbins,
-					vBlocks, hBlocks, vBlocks2, hBlocks2 )	
+			ref, _, err := match.EnrollWord( wordLabels[loopCt], takes, enrollParams )
+			takes = takes[:0]
+			if err != nil { // takes disagree; led triple flash, repeat all takes of this word
+				flashCount(led, 3)
+				continue
+			}
+			refs[loopCt] = ref
+			if only { // console 'enroll word'; the words after it are kept
+				loopCt, only = nWords, false
+				flashCount(led, 1)
+			} else {
+				loopCt++
+
+				// physical signifiers; flash signifies training
+				if loopCt == 1 { // first word; trained 'light'
+					flashOn(gpio10) // gpio10 flash and leave on
+					LightState = true
+				}
+				if loopCt == 2 { // second word; trained 'dark'
+					flashOff(gpio10) // gpio10 flash and leave off
+					LightState = false
+				}
+				if loopCt > 2 { // further words; led flashes the word number
+					flashCount(led, loopCt)
+				}
 			}
-		} // end if loopCt < 2
-		loopCt++
+			if loopCt == nWords && flash != nil { // training complete; keep references across power cycles
+				err = store.Save(flash, store.Record{ Params: storeParams, Refs: refs })
+				if err != nil {
+					flashCount(led, 5) // references work until power off, but were not saved
+				}
+			}
+			continue
+		} // end if loopCt < nWords
 
-		U16Spect, bIsNoise := CreateU16SpectFromU16 ( uBuf, HammingFftPoints,
-			Tbins, Fbins, buf_size, SpectThresh )
+		U16Spect, bIsNoise := dsp.CreateU16SpectConfig ( uBuf, HammingFftPoints, cfg, noiseThresh )
 		// fmt.Println("--debug-- U16Spect:", U16Spect[0][0:32],"\n\r")
 
 		if bIsNoise {
@@ -144,35 +306,27 @@ func main() {
 			continue
 		}
 
-		isLight := ReduceWordDetect(
-			U16Spect, iSpectRefReducedLight, iSpectRefReducedDark, SpectThresh, buf_size, Fbins, Tbins,
-			vBlocks, hBlocks, vBlocks2, hBlocks2 )
+		detection := match.DetectConfig( U16Spect, bIsNoise, refs, cfg )
+		st.Last = detection
+		st.Detections++
+		if st.Diags && st.Binary {
+			enc.Detection( detection )
+		}
 
 		// physical signifiers
-		if loopCt < 3 {  // training
-			if loopCt == 1 { 
-				// first word; flash signifies training; note LoopCt was inc'd; trained 'light'
-				// fmt.Println("loopCt == 1\n\r")
-				flashOn(gpio10) // gpio10 flash and leave on
-				LightState = true
-			}
-			if loopCt == 2 { // second word; flash to signifies training; trained 'dark'
-				// fmt.Println("loopCt == 2\n\r")
-				flashOff(gpio10) // gpio10 flash and leave off
-				LightState = false
-			}
-		} else {  // not training
-			if isLight == 1 {
-				// fmt.Println("--d-- isLight:", isLight, "\n\r")
-				gpio10.High()
-				LightState = true
-			}
-			if isLight == 0 {
-				gpio10.Low()
-				LightState = false
-			}
-			// ReduceWordDetect() may return '3' or other to signify 'word not detected' 
-		} // end if loopCt < 3
+		// fmt.Println("--d-- detection:", detection.Outcome, detection.Label, detection.Confidence, "\n\r")
+		if st.Output != console.OutputAuto { // console 'output' forces gpio10
+			continue
+		}
+		if detection.Outcome == match.Match && detection.Word == 0 {
+			gpio10.High()
+			LightState = true
+		}
+		if detection.Outcome == match.Match && detection.Word == 1 {
+			gpio10.Low()
+			LightState = false
+		}
+		// words > 1, and Ambiguous, NoMatch and Noise outcomes leave gpio10 unchanged
 		
 		// U16Spect = nil // --dev--
 		// runtime.GC()   // --dev-- 
@@ -198,6 +352,16 @@ func flashOff( gpioPin machine.Pin ) {
 	gpioPin.Low()
 }
 
+// flashCount flashes the received gpio pin 'n' times and leaves it in the off state
+func flashCount( gpioPin machine.Pin, n int ) {
+	for i:=0; i<n; i++ {
+		gpioPin.High()
+		time.Sleep(time.Millisecond * 200) 
+		gpioPin.Low()
+		time.Sleep(time.Millisecond * 200) 
+	}
+}
+
 // uartHeader outputs Tag_file and 'filename' to stdout (uart)
 // Transfer is ongoing until Tag_eot is sent to stdout (uart)
 // Uart assumes receipt of Tag_eod to end the file start created here
@@ -212,13 +376,29 @@ func uartFooter() {
 	fmt.Printf("%s\n\r", Tag_eot)
 }
 
-// captureDiags outputs the spectrogram and pooling arrays to stdout, intended
-// for uart capture.  Arrays are wrapped with tags to assist parsing.
-func captureDiags( uBuf []uint16, U16SpectRef [][]uint16,
-	iSpectRefReducedLight_PoolAvg, iSpectRefReducedLight [][]int ) { 
-	// create --uart out-- files for *_xt.dat, *_spect.dat, *_pool1/2.dat
+// noiseDiags outputs the noise floor estimate, the VAD thresholds derived from it, and
+// capture segment 'seg' as one '--noise--' line
+func noiseDiags( noise *adc.NoiseFloor, seg adc.Segment, noiseThresh uint16 ) {
+	start, stop := noise.Thresholds()
+	fmt.Printf("--noise-- floor %d rms %d frames %d start %d stop %d peak %d seg %d %d %s clicks %d\n\r",
+		noise.Energy, noise.Rms(), noise.Frames, start, stop, noiseThresh,
+		seg.Start, seg.End, seg.Reason, seg.Clicks)
+}
+
+// captureDiags outputs the params, spectrogram and pooling arrays, and the noise floor, to
+// stdout, intended for uart capture.  Arrays are wrapped with tags to assist parsing.
+func captureDiags( cfg config.Config, uBuf []uint16, U16SpectRef [][]uint16,
+	iSpectRefReducedLight_PoolAvg, iSpectRefReducedLight [][]int,
+	noise *adc.NoiseFloor, seg adc.Segment, noiseThresh uint16 ) { 
+	// create --uart out-- files for *_params.dat, *_xt.dat, *_spect.dat, *_pool1/2.dat, *_noise.dat
 	// '--' tagging embedded in uartHeader(); requires --eod-- to close file write
-	uartHeader("file00_xt.dat") // u16 decimal 0-65535 (expt 2 16) 65536
+	uartHeader("file00_params.dat") // 'name value' lines; array shapes follow from these
+	for i, v := range frame.ParamValues(cfg) { // the fields and order of a frame.TypeParams
+		fmt.Printf("%s %d\n\r", frame.ParamNames[i], v)
+	}
+	fmt.Println("--eod--","\n\r") // end of file00_params.dat
+
+	fmt.Println(Tag_file, "--file00_xt.dat--","\n\r") // u16 decimal 0-65535 (expt 2 16) 65536 // u16 decimal 0-65535 (expt 2 16) 65536
 	for _,v := range uBuf[:] {
 		fmt.Println(v,"\n\r")
 	}
@@ -250,9 +430,27 @@ func captureDiags( uBuf []uint16, U16SpectRef [][]uint16,
 		fmt.Println("\n\r")
 	}
 	fmt.Println("--eod--","\n\r") // end of file00_pool2.dat
+
+	fmt.Println(Tag_file, "--file00_noise.dat--","\n\r")
+	noiseDiags( noise, seg, noiseThresh )
+	fmt.Println("--eod--","\n\r") // end of file00_noise.dat
 	uartFooter() // --eot--
 } // end func captureDiags( uBuf []uint16, U16SpectRef [][]uint16,...
 
+// frameDiags sends captureDiags' arrays as detectword/frame frames; a compact, checksummed
+// alternative to the tagged text, received with 'dwuart -binary'
+func frameDiags( enc *frame.Encoder, cfg config.Config, uBuf []uint16, U16SpectRef [][]uint16,
+	iSpectRefReducedLight_PoolAvg, iSpectRefReducedLight [][]int,
+	noise *adc.NoiseFloor, seg adc.Segment, noiseThresh uint16 ) {
+	enc.Params( cfg ) // starts the transmission
+	enc.Samples( uBuf )
+	enc.Spect( U16SpectRef )
+	enc.Pool( frame.TypePool1, iSpectRefReducedLight_PoolAvg )
+	enc.Pool( frame.TypePool2, iSpectRefReducedLight )
+	enc.Noise( noise, seg, noiseThresh )
+	enc.End()
+} // end func frameDiags
+
 // Notes
 // fmt.Println("--debug-- GetFunctionName() test :", GetFunctionName(U16HexList2GoIncludeVar))
 
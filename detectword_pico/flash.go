@@ -0,0 +1,28 @@
+// @file TinyGo/detectword_pico/flash.go
+// @date 2026.10.18
+// @info enrolled reference store on the pico flash, machine.Flash; the default build, with a
+//       TinyGo release providing machine.Flash. TinyGo v0.21 has none; build it '-tags noflash'
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 built by default; '-tags noflash' opts out, was '-tags flash' to opt in
+
+// @build: tinygo flash -target=pico
+
+//go:build !noflash
+// +build !noflash
+
+package main
+
+import (
+	"machine"
+
+	"localhost/detectword/store"
+)
+
+// refStore returns the flash device references are saved to and loaded from
+func refStore() store.BlockDevice {
+	return machine.Flash
+}
@@ -0,0 +1,24 @@
+// @file TinyGo/detectword_pico/flash_none.go
+// @date 2026.10.18
+// @info no enrolled reference store; '-tags noflash', for TinyGo v0.21 without machine.Flash,
+//       trains every word at power on. See flash.go.
+
+// Copyright 2026 RC Schuler. All rights reserved.
+// Use of this source code is governed by a GNU V3
+// license that can be found in the LICENSE file.
+
+// @date 2026.10.18 built with '-tags noflash', was the default
+
+// @build: tinygo flash -target=pico -tags noflash
+
+//go:build noflash
+// +build noflash
+
+package main
+
+import "localhost/detectword/store"
+
+// refStore returns nil; references are kept until power off only
+func refStore() store.BlockDevice {
+	return nil
+}
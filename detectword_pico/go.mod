@@ -4,4 +4,9 @@ go 1.17
 
 replace localhost/adc => ../adc
 
-require localhost/adc v0.0.0-00010101000000-000000000000
+replace localhost/detectword => ../detectword
+
+require (
+	localhost/adc v0.0.0-00010101000000-000000000000
+	localhost/detectword v0.0.0-00010101000000-000000000000
+)